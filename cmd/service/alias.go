@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/configs"
+)
+
+const aliasUsage = `usage:
+  animeman alias list
+  animeman alias add <title> <term>
+  animeman alias remove <title> [term]`
+
+// runAliasCommand manages the title aliases in the config file.
+// Titles can be anime list titles or identity keys like anilist:21.
+func runAliasCommand(configPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(aliasUsage)
+	}
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "list" && len(args) == 0:
+		aliases, err := configs.ReadAliases(configPath)
+		if err != nil {
+			return err
+		}
+		titles := make([]string, 0, len(aliases))
+		for title := range aliases {
+			titles = append(titles, title)
+		}
+		slices.Sort(titles)
+		for _, title := range titles {
+			fmt.Printf("%s: %s\n", title, strings.Join(aliases[title], ", "))
+		}
+		return nil
+	case cmd == "add" && len(args) == 2:
+		return configs.AddAlias(configPath, args[0], args[1])
+	case cmd == "remove" && len(args) == 1:
+		return configs.RemoveAlias(configPath, args[0], "")
+	case cmd == "remove" && len(args) == 2:
+		return configs.RemoveAlias(configPath, args[0], args[1])
+	default:
+		return errors.New(aliasUsage)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runCommand runs a command line subcommand, like alias, corpus or import, exiting on errors.
+// It returns false when the arguments aren't a subcommand, so the service should start.
+func runCommand(configPath string, args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	var err error
+	switch args[0] {
+	case "alias":
+		err = runAliasCommand(configPath, args[1:])
+	case "corpus":
+		err = runCorpusCommand(args[1:])
+	case "import":
+		err = runImportCommand(configPath, args[1:])
+	default:
+		return false
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sonalys/animeman/internal/integrations/nyaa"
+	"github.com/sonalys/animeman/internal/parser"
+)
+
+const corpusUsage = `usage:
+  animeman corpus check <corpus.json>
+  animeman corpus collect <corpus.json> <search query>`
+
+// runCorpusCommand checks the parser against a golden corpus of release titles, or grows it from nyaa.
+func runCorpusCommand(args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "check":
+		return checkCorpus(args[1])
+	case len(args) >= 3 && args[0] == "collect":
+		return collectCorpus(args[1], strings.Join(args[2:], " "))
+	default:
+		return errors.New(corpusUsage)
+	}
+}
+
+func checkCorpus(path string) error {
+	entries, err := parser.ReadCorpus(path)
+	if err != nil {
+		return err
+	}
+
+	report := parser.CheckCorpus(entries)
+
+	for _, diff := range report.Diffs {
+		fmt.Printf("%s\n  %s: want %v, got %v\n", diff.Title, diff.Field, diff.Want, diff.Got)
+	}
+
+	fields := make([]string, 0, len(report.FieldMismatches))
+	for field := range report.FieldMismatches {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		fmt.Printf("%s: %d mismatches\n", field, report.FieldMismatches[field])
+	}
+
+	if report.New > 0 {
+		fmt.Printf("%d titles without expected metadata\n", report.New)
+	}
+
+	checked := report.Total - report.New
+	fmt.Printf("accuracy: %.2f%% (%d/%d)\n", report.Accuracy()*100, checked-report.Mismatches, checked)
+
+	return report.Err()
+}
+
+// collectCorpus appends new release titles from a nyaa search to the corpus, with their current parser output.
+// The expected metadata should be reviewed before committing the corpus.
+func collectCorpus(path, query string) error {
+	entries, err := parser.ReadCorpus(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	client := nyaa.New(&http.Client{Transport: defaultTransport, Timeout: 15 * time.Second}, nyaa.Config{})
+	items, err := client.List(ctx, nyaa.ListOptions{Titles: []string{query}})
+	if err != nil {
+		return fmt.Errorf("searching nyaa: %w", err)
+	}
+
+	var added int
+	for _, item := range items {
+		if slices.ContainsFunc(entries, func(entry parser.CorpusEntry) bool { return entry.Title == item.Title }) {
+			continue
+		}
+		entries = append(entries, parser.UpdateCorpus([]parser.CorpusEntry{{Title: item.Title}})...)
+		added++
+	}
+
+	if err := parser.WriteCorpus(path, entries); err != nil {
+		return err
+	}
+
+	fmt.Printf("added %d titles, review their expected metadata before committing\n", added)
+
+	return nil
+}
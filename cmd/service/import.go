@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/animedb"
+	"github.com/sonalys/animeman/internal/configs"
+	"github.com/sonalys/animeman/internal/discovery"
+	"github.com/sonalys/animeman/internal/store"
+)
+
+const importUsage = `usage:
+  animeman import [--dry-run] <directory>`
+
+// runImportCommand imports video files from a directory tree as owned episodes, so they are not downloaded again.
+// Dry runs only print which files match the anime list.
+func runImportCommand(configPath string, args []string) error {
+	dryRun := len(args) > 0 && args[0] == "--dry-run"
+	if dryRun {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return errors.New(importUsage)
+	}
+
+	config, err := configs.ReadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("config is not valid: %w", err)
+	}
+
+	episodeMappings, err := configs.ReadEpisodeMappings(config.EpisodeMappingsPath)
+	if err != nil {
+		return fmt.Errorf("episode mappings are not valid: %w", err)
+	}
+
+	animeDB, err := animedb.Load(config.AnimeDB.Paths...)
+	if err != nil {
+		return fmt.Errorf("anime database is not valid: %w", err)
+	}
+
+	ownedStore, err := store.OpenOwned(filepath.Join(config.DataDir, "owned.json"))
+	if err != nil {
+		return fmt.Errorf("could not open owned episodes store: %w", err)
+	}
+
+	c := discovery.New(discovery.Dependencies{
+		AnimeListClient: initializeAnimeList(config.AnimeListConfig),
+		AnimeDB:         animeDB,
+		OwnedStore:      ownedStore,
+		Config:          newDiscoveryConfig(config, episodeMappings),
+	})
+
+	report, err := c.ImportLibrary(context.Background(), args[0], dryRun)
+	if err != nil {
+		return err
+	}
+
+	// Files are walked in lexical order, grouping them by title keeps that order within each show.
+	slices.SortStableFunc(report.Matched, func(a, b discovery.ImportedFile) int {
+		return strings.Compare(a.Title, b.Title)
+	})
+
+	for _, file := range report.Matched {
+		fmt.Printf("%s: %s %s (%.2f)\n", file.Path, file.Title, file.Tag.String(), file.Score)
+	}
+	for _, path := range report.Unmatched {
+		fmt.Printf("%s: no match\n", path)
+	}
+
+	fmt.Printf("matched %d files, %d without a match\n", len(report.Matched), len(report.Unmatched))
+	if dryRun {
+		fmt.Println("dry run, no episodes were recorded")
+	}
+
+	return nil
+}
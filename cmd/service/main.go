@@ -5,19 +5,26 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/animedb"
 	"github.com/sonalys/animeman/internal/configs"
 	"github.com/sonalys/animeman/internal/discovery"
 	"github.com/sonalys/animeman/internal/integrations/anilist"
+	"github.com/sonalys/animeman/internal/integrations/jellyfin"
 	"github.com/sonalys/animeman/internal/integrations/myanimelist"
 	"github.com/sonalys/animeman/internal/integrations/nyaa"
+	"github.com/sonalys/animeman/internal/integrations/plex"
 	"github.com/sonalys/animeman/internal/integrations/qbittorrent"
 	"github.com/sonalys/animeman/internal/roundtripper"
+	"github.com/sonalys/animeman/internal/store"
 	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 	"golang.org/x/time/rate"
 )
 
@@ -43,7 +50,12 @@ func init() {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 }
 
-func initializeAnimeList(c configs.AnimeListConfig) discovery.AnimeListSource {
+type animeListClient interface {
+	discovery.AnimeListSource
+	discovery.AnimeListWriter
+}
+
+func initializeAnimeList(c configs.AnimeListConfig) animeListClient {
 	httpClient := &http.Client{
 		Transport: roundtripper.NewRateLimitedTransport(
 			defaultTransport,
@@ -54,9 +66,9 @@ func initializeAnimeList(c configs.AnimeListConfig) discovery.AnimeListSource {
 
 	switch c.Type {
 	case configs.AnimeListTypeMAL:
-		return myanimelist.New(httpClient, c.Username, c.CacheTTL)
+		return myanimelist.New(httpClient, c.Username, c.Sync.AccessToken, c.CacheTTL)
 	case configs.AnimeListTypeAnilist:
-		return anilist.New(httpClient, c.Username, c.CacheTTL)
+		return anilist.New(httpClient, c.Username, c.Sync.AccessToken, c.CacheTTL)
 	default:
 		log.Panic().Msgf("animeListType %s not implemented", c.Type)
 	}
@@ -66,23 +78,140 @@ func initializeAnimeList(c configs.AnimeListConfig) discovery.AnimeListSource {
 func initializeTorrentClient(ctx context.Context, c configs.TorrentConfig) discovery.TorrentClient {
 	switch c.Type {
 	case configs.TorrentClientTypeQBittorrent:
-		return qbittorrent.New(ctx, c.Host, c.Username, c.Password)
+		api := qbittorrent.New(c.Host, c.Username, c.Password)
+		if version, err := api.Version(ctx); err != nil {
+			log.Warn().Msgf("could not connect to qBittorrent, retrying on the next scan: %s", err)
+		} else {
+			log.Info().Msgf("connected to qBittorrent:%s", version)
+		}
+		return api
 	default:
 		log.Panic().Msgf("animeListType %s not implemented", c.Type)
 	}
 	return nil
 }
 
+// initializeMediaServer returns nil when no media server is configured.
+func initializeMediaServer(c configs.MediaServerConfig) discovery.MediaServer {
+	httpClient := &http.Client{
+		Transport: defaultTransport,
+		Timeout:   15 * time.Second,
+	}
+
+	switch c.Type {
+	case "":
+		return nil
+	case configs.MediaServerTypeJellyfin, configs.MediaServerTypeEmby:
+		return jellyfin.New(httpClient, c.Host, c.Token)
+	case configs.MediaServerTypePlex:
+		return plex.New(httpClient, c.Host, c.Token, c.SectionID)
+	default:
+		log.Panic().Msgf("mediaServerType %s not implemented", c.Type)
+	}
+	return nil
+}
+
+func convertShowSeedingLimits(shows map[string]configs.SeedingLimits) map[string]torrentclient.ShareLimits {
+	out := make(map[string]torrentclient.ShareLimits, len(shows))
+	for show, limits := range shows {
+		out[show] = limits.Convert()
+	}
+	return out
+}
+
+// newDiscoveryConfig maps the config file into the discovery config.
+func newDiscoveryConfig(config configs.Config, episodeMappings map[string][]int) discovery.Config {
+	return discovery.Config{
+		ListStatuses: utils.Map(config.ListStatuses, func(s configs.ListStatus) animelist.ListStatus {
+			return s.Convert()
+		}),
+		PlanToWatchAiringWithin: config.PlanToWatch.AiringWithin,
+		PlanToWatchBatchOnly:    config.PlanToWatch.BatchOnly,
+		EpisodesAhead:           config.Progress.EpisodesAhead,
+		SkipWatched:             config.Progress.SkipWatched,
+		SyncProgress:            config.Sync.Enabled,
+		EpisodeMappings:         episodeMappings,
+		Aliases:                 config.Aliases,
+		TitleMatchThreshold:     config.TitleMatchThreshold,
+		SearchSuffix:            config.SearchSuffix,
+		Sources:                 config.Sources,
+		Qualitites:              config.Qualities,
+		Category:                config.Category,
+		RenameTorrent:           *utils.Coalesce(config.RenameTorrent, utils.Pointer(true)),
+		DownloadPath:            config.DownloadPath,
+		CreateShowFolder:        config.CreateShowFolder,
+		ReplaceUpgrades:         config.ReplaceUpgrades,
+		BatchReplaceEpisodes:    config.Batches.ReplaceEpisodes,
+		BatchReplaceSources:     config.Batches.ReplaceSources,
+		BatchRemoveReplaced:     config.Batches.RemoveReplaced,
+		BatchFillGaps:           config.Batches.FillGaps,
+		SeedingLimits:           config.Lifecycle.SeedingLimits.Convert(),
+		ShowSeedingLimits:       convertShowSeedingLimits(config.Lifecycle.Shows),
+		RemoveOnSeedingLimit:    config.Lifecycle.RemoveOnLimit,
+		RemoveListStatuses: utils.Map(config.Lifecycle.RemoveStatuses, func(s configs.ListStatus) animelist.ListStatus {
+			return s.Convert()
+		}),
+		RemoveKeepFiles:        *utils.Coalesce(config.Lifecycle.KeepFiles, utils.Pointer(true)),
+		StalledTimeout:         config.StalledTimeout,
+		CategorySavePath:       config.CategorySavePath,
+		CompletedPath:          config.CompletedPath,
+		SkipExtras:             config.SkipExtras,
+		AddToTopOfQueue:        config.Download.TopOfQueue,
+		SequentialDownload:     config.Download.Sequential,
+		FirstLastPiecePriority: config.Download.FirstLastPiece,
+		TransferLimits:         config.Download.Limits(),
+		LibraryPath:            config.Library.Path,
+		LibraryMode:            config.Library.Mode,
+		LibraryDownloadPath:    config.Library.DownloadPath,
+		PollFrequency:          config.PollFrequency,
+	}
+}
+
 func main() {
+	configPath := utils.Coalesce(os.Getenv("CONFIG_PATH"), "config.yaml")
+	if runCommand(configPath, os.Args[1:]) {
+		return
+	}
+
 	log.Info().Msgf("starting Animeman [%s]", version)
 
-	config, err := configs.ReadConfig(utils.Coalesce(os.Getenv("CONFIG_PATH"), "config.yaml"))
+	config, err := configs.ReadConfig(configPath)
 	if err != nil {
 		log.Fatal().Msgf("config is not valid: %s", err)
 	}
 
 	zerolog.SetGlobalLevel(config.LogLevel.Convert())
 
+	episodeMappings, err := configs.ReadEpisodeMappings(config.EpisodeMappingsPath)
+	if err != nil {
+		log.Fatal().Msgf("episode mappings are not valid: %s", err)
+	}
+
+	animeDB, err := animedb.Load(config.AnimeDB.Paths...)
+	if err != nil {
+		log.Fatal().Msgf("anime database is not valid: %s", err)
+	}
+
+	blocklist, err := store.OpenBlocklist(filepath.Join(config.DataDir, "blocklist.json"))
+	if err != nil {
+		log.Fatal().Msgf("could not open blocklist: %s", err)
+	}
+
+	processedStore, err := store.OpenProcessed(filepath.Join(config.DataDir, "processed.json"))
+	if err != nil {
+		log.Fatal().Msgf("could not open processed torrents store: %s", err)
+	}
+
+	ownedStore, err := store.OpenOwned(filepath.Join(config.DataDir, "owned.json"))
+	if err != nil {
+		log.Fatal().Msgf("could not open owned episodes store: %s", err)
+	}
+
+	showStore, err := store.OpenShows(filepath.Join(config.DataDir, "shows.json"))
+	if err != nil {
+		log.Fatal().Msgf("could not open shows store: %s", err)
+	}
+
 	ctx, done := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 
 	nyaaClient := &http.Client{
@@ -98,20 +227,20 @@ func main() {
 		ListParameters: config.CustomParameters,
 	}
 
+	animeList := initializeAnimeList(config.AnimeListConfig)
+
 	c := discovery.New(discovery.Dependencies{
 		NYAA:            nyaa.New(nyaaClient, nyaaConfig),
-		AnimeListClient: initializeAnimeList(config.AnimeListConfig),
+		AnimeListClient: animeList,
+		AnimeListWriter: animeList,
 		TorrentClient:   initializeTorrentClient(ctx, config.TorrentConfig),
-		Config: discovery.Config{
-			SearchSuffix:     config.SearchSuffix,
-			Sources:          config.Sources,
-			Qualitites:       config.Qualities,
-			Category:         config.Category,
-			RenameTorrent:    *utils.Coalesce(config.RenameTorrent, utils.Pointer(true)),
-			DownloadPath:     config.DownloadPath,
-			CreateShowFolder: config.CreateShowFolder,
-			PollFrequency:    config.PollFrequency,
-		},
+		AnimeDB:         animeDB,
+		ShowStore:       showStore,
+		Blocklist:       blocklist,
+		ProcessedStore:  processedStore,
+		OwnedStore:      ownedStore,
+		MediaServer:     initializeMediaServer(config.MediaServer),
+		Config:          newDiscoveryConfig(config, episodeMappings),
 	})
 	if err := c.Start(ctx); err != nil {
 		log.Error().Msgf("failed to shutdown: %s", err)
@@ -0,0 +1,188 @@
+// Package animedb resolves anime identifiers across providers from offline mapping datasets.
+//
+// Supported datasets:
+//   - manami-project anime-offline-database: https://github.com/manami-project/anime-offline-database
+//   - Fribb anime-lists: https://github.com/Fribb/anime-lists
+package animedb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+type (
+	// Record is a single anime from the mapping dataset.
+	Record struct {
+		IDs      animelist.IDs
+		Title    string
+		Synonyms []string
+	}
+
+	// DB is an in-memory index of records by provider identifier.
+	DB struct {
+		records []Record
+		index   map[string]int
+	}
+)
+
+func New(records ...Record) *DB {
+	db := &DB{
+		index: make(map[string]int),
+	}
+	for _, record := range records {
+		db.add(record)
+	}
+	return db
+}
+
+// Load reads all the given dataset files into a new DB.
+// Records from different files are merged when they share an identifier.
+func Load(paths ...string) (*DB, error) {
+	db := New()
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", path, err)
+		}
+		err = db.Read(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return db, nil
+}
+
+// Read reads a dataset into the DB, detecting it's format.
+func (db *DB) Read(r io.Reader) error {
+	reader := bufio.NewReader(r)
+
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		return fmt.Errorf("reading dataset: %w", err)
+	}
+
+	var records []Record
+
+	switch first {
+	case '{':
+		records, err = readManami(reader)
+	case '[':
+		records, err = readFribb(reader)
+	default:
+		return fmt.Errorf("unknown dataset format")
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		db.add(record)
+	}
+
+	return nil
+}
+
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := r.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func decode(r io.Reader, v any) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("decoding dataset: %w", err)
+	}
+	return nil
+}
+
+func indexKeys(ids animelist.IDs) []string {
+	keys := make([]string, 0, 5)
+	for _, id := range []animelist.IDs{
+		{AniList: ids.AniList},
+		{MAL: ids.MAL},
+		{AniDB: ids.AniDB},
+		{Kitsu: ids.Kitsu},
+		{TVDB: ids.TVDB},
+	} {
+		if key := id.Key(); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// add indexes a record, merging it with an existing record sharing any identifier.
+// TVDB identifiers are shared between seasons, so they are never used for merging,
+// and only the first record with a TVDB identifier is indexed by it.
+func (db *DB) add(record Record) {
+	withoutTVDB := record.IDs
+	withoutTVDB.TVDB = 0
+
+	pos := -1
+	for _, key := range indexKeys(withoutTVDB) {
+		if i, ok := db.index[key]; ok {
+			pos = i
+			break
+		}
+	}
+
+	if pos == -1 {
+		pos = len(db.records)
+		db.records = append(db.records, Record{})
+	}
+
+	existing := &db.records[pos]
+	existing.IDs = existing.IDs.Merge(record.IDs)
+	if existing.Title == "" {
+		existing.Title = record.Title
+	}
+	for _, synonym := range record.Synonyms {
+		if !slices.Contains(existing.Synonyms, synonym) {
+			existing.Synonyms = append(existing.Synonyms, synonym)
+		}
+	}
+
+	tvdbKey := animelist.IDs{TVDB: existing.IDs.TVDB}.Key()
+	for _, key := range indexKeys(existing.IDs) {
+		if _, ok := db.index[key]; ok && key == tvdbKey {
+			continue
+		}
+		db.index[key] = pos
+	}
+}
+
+// Resolve finds the record matching any of the given identifiers.
+// TVDB identifiers are only used when no other identifier matches.
+func (db *DB) Resolve(ids animelist.IDs) (Record, bool) {
+	if db == nil {
+		return Record{}, false
+	}
+	for _, key := range indexKeys(ids) {
+		if i, ok := db.index[key]; ok {
+			return db.records[i], true
+		}
+	}
+	return Record{}, false
+}
+
+// Len returns the number of records in the DB.
+func (db *DB) Len() int {
+	return len(db.records)
+}
@@ -0,0 +1,77 @@
+package animedb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/stretchr/testify/require"
+)
+
+const manamiDatasetJSON = `{
+	"data": [
+		{
+			"sources": [
+				"https://anidb.net/anime/4563",
+				"https://anilist.co/anime/1",
+				"https://kitsu.app/anime/1",
+				"https://myanimelist.net/anime/1"
+			],
+			"title": "Cowboy Bebop",
+			"synonyms": ["カウボーイビバップ", "Cowboy Bebop TV"]
+		},
+		{
+			"sources": ["https://notify.moe/anime/abc"],
+			"title": "Unknown Source"
+		}
+	]
+}`
+
+const fribbDatasetJSON = `[
+	{"anidb_id": 4563, "anilist_id": 1, "mal_id": 1, "kitsu_id": 1, "thetvdb_id": 76885},
+	{"anidb_id": 5, "mal_id": 5, "thetvdb_id": 76885}
+]`
+
+func TestDB_Read(t *testing.T) {
+	db := New()
+	require.NoError(t, db.Read(strings.NewReader(manamiDatasetJSON)))
+	require.NoError(t, db.Read(strings.NewReader(fribbDatasetJSON)))
+
+	require.Equal(t, 2, db.Len())
+
+	t.Run("resolve by any provider", func(t *testing.T) {
+		for _, ids := range []animelist.IDs{{AniList: 1}, {MAL: 1}, {AniDB: 4563}, {Kitsu: 1}} {
+			record, ok := db.Resolve(ids)
+			require.True(t, ok)
+			require.Equal(t, "Cowboy Bebop", record.Title)
+			require.Equal(t, animelist.IDs{AniList: 1, MAL: 1, AniDB: 4563, Kitsu: 1, TVDB: 76885}, record.IDs)
+			require.Equal(t, []string{"カウボーイビバップ", "Cowboy Bebop TV"}, record.Synonyms)
+		}
+	})
+
+	t.Run("shared tvdb id keeps first record", func(t *testing.T) {
+		record, ok := db.Resolve(animelist.IDs{TVDB: 76885})
+		require.True(t, ok)
+		require.Equal(t, 1, record.IDs.AniList)
+
+		record, ok = db.Resolve(animelist.IDs{MAL: 5})
+		require.True(t, ok)
+		require.Equal(t, 76885, record.IDs.TVDB)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, ok := db.Resolve(animelist.IDs{AniList: 999})
+		require.False(t, ok)
+	})
+
+	t.Run("nil db", func(t *testing.T) {
+		var db *DB
+		_, ok := db.Resolve(animelist.IDs{AniList: 1})
+		require.False(t, ok)
+	})
+}
+
+func TestDB_ReadInvalid(t *testing.T) {
+	require.Error(t, New().Read(strings.NewReader("not json")))
+	require.Error(t, New().Read(strings.NewReader("")))
+}
@@ -0,0 +1,38 @@
+package animedb
+
+import (
+	"io"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+type fribbEntry struct {
+	AniListID int `json:"anilist_id"`
+	MALID     int `json:"mal_id"`
+	AniDBID   int `json:"anidb_id"`
+	KitsuID   int `json:"kitsu_id"`
+	TVDBID    int `json:"thetvdb_id"`
+}
+
+func readFribb(r io.Reader) ([]Record, error) {
+	var dataset []fribbEntry
+	if err := decode(r, &dataset); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(dataset))
+	for _, entry := range dataset {
+		ids := animelist.IDs{
+			AniList: entry.AniListID,
+			MAL:     entry.MALID,
+			AniDB:   entry.AniDBID,
+			Kitsu:   entry.KitsuID,
+			TVDB:    entry.TVDBID,
+		}
+		if ids.IsZero() {
+			continue
+		}
+		records = append(records, Record{IDs: ids})
+	}
+	return records, nil
+}
@@ -0,0 +1,68 @@
+package animedb
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+type manamiDataset struct {
+	Data []struct {
+		Sources  []string `json:"sources"`
+		Title    string   `json:"title"`
+		Synonyms []string `json:"synonyms"`
+	} `json:"data"`
+}
+
+// Sources are provider URLs, like https://anilist.co/anime/1.
+var manamiSourceExpr = regexp.MustCompile(`^https?://(?:www\.)?([^/]+)/.*?(\d+)$`)
+
+func parseManamiSources(sources []string) animelist.IDs {
+	var ids animelist.IDs
+	for _, source := range sources {
+		matches := manamiSourceExpr.FindStringSubmatch(source)
+		if len(matches) < 3 {
+			continue
+		}
+		id, err := strconv.Atoi(matches[2])
+		if err != nil {
+			continue
+		}
+		switch matches[1] {
+		case "anilist.co":
+			ids.AniList = id
+		case "myanimelist.net":
+			ids.MAL = id
+		case "anidb.net":
+			ids.AniDB = id
+		case "kitsu.app", "kitsu.io":
+			ids.Kitsu = id
+		case "thetvdb.com":
+			ids.TVDB = id
+		}
+	}
+	return ids
+}
+
+func readManami(r io.Reader) ([]Record, error) {
+	var dataset manamiDataset
+	if err := decode(r, &dataset); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(dataset.Data))
+	for _, entry := range dataset.Data {
+		ids := parseManamiSources(entry.Sources)
+		if ids.IsZero() {
+			continue
+		}
+		records = append(records, Record{
+			IDs:      ids,
+			Title:    entry.Title,
+			Synonyms: entry.Synonyms,
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,123 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const aliasesKey = "aliases"
+
+// readNode reads a yaml file as a node, preserving comments and ordering when written back.
+func readNode(path string) (*yaml.Node, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening config: %w", err)
+	}
+	defer file.Close()
+	var doc yaml.Node
+	if err := yaml.NewDecoder(file).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("reading config: expected a yaml mapping")
+	}
+	return &doc, nil
+}
+
+func writeNode(path string, doc *yaml.Node) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("opening config: %w", err)
+	}
+	defer file.Close()
+	if err := yaml.NewEncoder(file).Encode(doc); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node of key inside a mapping node, creating it with kind if create is true.
+func mappingValue(mapping *yaml.Node, key string, kind yaml.Kind, create bool) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	if !create {
+		return nil
+	}
+	value := &yaml.Node{Kind: kind}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+func removeMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// AddAlias adds a search and match term for an anime list title in the config file.
+func AddAlias(path, title, term string) error {
+	doc, err := readNode(path)
+	if err != nil {
+		return err
+	}
+	aliases := mappingValue(doc.Content[0], aliasesKey, yaml.MappingNode, true)
+	terms := mappingValue(aliases, title, yaml.SequenceNode, true)
+	for _, node := range terms.Content {
+		if node.Value == term {
+			return nil
+		}
+	}
+	terms.Content = append(terms.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: term})
+	return writeNode(path, doc)
+}
+
+// RemoveAlias removes a search and match term for an anime list title from the config file.
+// If term is empty, all terms from the title are removed.
+func RemoveAlias(path, title, term string) error {
+	doc, err := readNode(path)
+	if err != nil {
+		return err
+	}
+	aliases := mappingValue(doc.Content[0], aliasesKey, yaml.MappingNode, false)
+	if aliases == nil {
+		return nil
+	}
+	if terms := mappingValue(aliases, title, yaml.SequenceNode, false); terms != nil && term != "" {
+		terms.Content = slices.DeleteFunc(terms.Content, func(node *yaml.Node) bool {
+			return node.Value == term
+		})
+		if len(terms.Content) > 0 {
+			return writeNode(path, doc)
+		}
+	}
+	removeMappingKey(aliases, title)
+	if len(aliases.Content) == 0 {
+		removeMappingKey(doc.Content[0], aliasesKey)
+	}
+	return writeNode(path, doc)
+}
+
+// ReadAliases reads only the aliases from the config file, without validating the rest of it.
+func ReadAliases(path string) (map[string][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening config: %w", err)
+	}
+	defer file.Close()
+	var config struct {
+		Aliases map[string][]string `yaml:"aliases"`
+	}
+	if err := yaml.NewDecoder(file).Decode(&config); err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	return config.Aliases, nil
+}
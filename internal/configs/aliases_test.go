@@ -0,0 +1,36 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("# my config\nlogLevel: info # comment\n"), 0o644))
+
+	readAliases := func() map[string][]string {
+		aliases, err := ReadAliases(path)
+		require.NoError(t, err)
+		return aliases
+	}
+
+	require.NoError(t, AddAlias(path, "Shingeki no Kyojin", "SnK"))
+	require.NoError(t, AddAlias(path, "Shingeki no Kyojin", "AoT"))
+	require.NoError(t, AddAlias(path, "Shingeki no Kyojin", "AoT"))
+	require.Equal(t, map[string][]string{"Shingeki no Kyojin": {"SnK", "AoT"}}, readAliases())
+
+	require.NoError(t, RemoveAlias(path, "Shingeki no Kyojin", "SnK"))
+	require.Equal(t, map[string][]string{"Shingeki no Kyojin": {"AoT"}}, readAliases())
+
+	require.NoError(t, RemoveAlias(path, "Shingeki no Kyojin", ""))
+	require.Empty(t, readAliases())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "# my config")
+	require.Contains(t, string(content), "logLevel: info # comment")
+}
@@ -3,11 +3,16 @@ package configs
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/library"
 	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 	"gopkg.in/yaml.v3"
 )
 
@@ -25,10 +30,54 @@ func (t AnimeListType) Validate() error {
 	return nil
 }
 
+type ListStatus string
+
+func (s ListStatus) Validate() error {
+	if _, ok := animelist.ParseListStatus(string(s)); !ok || s == "unknown" {
+		return fmt.Errorf("'%s' is invalid. should be [watching,completed,onHold,dropped,planToWatch,all]", s)
+	}
+	return nil
+}
+
+func (s ListStatus) Convert() animelist.ListStatus {
+	status, _ := animelist.ParseListStatus(string(s))
+	return status
+}
+
+type PlanToWatchConfig struct {
+	// AiringWithin only includes plan to watch entries starting to air within the given window.
+	// Zero means no restriction. It can't be used with BatchOnly.
+	AiringWithin time.Duration `yaml:"airingWithin"`
+	// BatchOnly only downloads batches for plan to watch entries, once they have aired.
+	// It can't be used with AiringWithin.
+	BatchOnly bool `yaml:"batchOnly"`
+}
+
+type ProgressConfig struct {
+	// EpisodesAhead only downloads up to your list progress plus the given number of episodes.
+	// Zero disables the limit.
+	EpisodesAhead int `yaml:"episodesAhead"`
+	// SkipWatched avoids downloading episodes already marked as watched.
+	SkipWatched bool `yaml:"skipWatched"`
+}
+
+type SyncConfig struct {
+	// Enabled updates your anime list progress once episodes finish downloading.
+	Enabled bool `yaml:"enabled"`
+	// AccessToken is the OAuth access token from your anime list provider.
+	AccessToken string `yaml:"accessToken"`
+}
+
 type AnimeListConfig struct {
-	Type     AnimeListType `yaml:"type"`
-	Username string        `yaml:"username"`
-	CacheTTL time.Duration `yaml:"cacheTTL"`
+	Type         AnimeListType     `yaml:"type"`
+	Username     string            `yaml:"username"`
+	CacheTTL     time.Duration     `yaml:"cacheTTL"`
+	ListStatuses []ListStatus      `yaml:"listStatuses"`
+	PlanToWatch  PlanToWatchConfig `yaml:"planToWatch"`
+	Progress     ProgressConfig    `yaml:"progress"`
+	Sync         SyncConfig        `yaml:"sync"`
+	// EpisodeMappingsPath is an optional yaml file mapping titles to the episode count of each season.
+	EpisodeMappingsPath string `yaml:"episodeMappingsPath,omitempty"`
 }
 
 func (c *AnimeListConfig) Validate() error {
@@ -44,6 +93,31 @@ func (c *AnimeListConfig) Validate() error {
 	if c.CacheTTL < 30*time.Minute {
 		return fmt.Errorf("cacheTTL: must be at least 30 minutes")
 	}
+	if len(c.ListStatuses) == 0 {
+		c.ListStatuses = []ListStatus{"watching"}
+	}
+	for i, status := range c.ListStatuses {
+		if err := status.Validate(); err != nil {
+			return fmt.Errorf("listStatuses[%d]: %w", i, err)
+		}
+	}
+	if c.PlanToWatch.AiringWithin < 0 {
+		return fmt.Errorf("planToWatch.airingWithin: must not be negative")
+	}
+	if c.PlanToWatch.AiringWithin > 0 && c.PlanToWatch.BatchOnly {
+		// Batch only entries must have finished airing, so they already started airing before any window.
+		return fmt.Errorf("planToWatch.batchOnly: can't be used with planToWatch.airingWithin")
+	}
+	if c.Progress.EpisodesAhead < 0 {
+		return fmt.Errorf("progress.episodesAhead: must not be negative")
+	}
+	if c.Sync.Enabled && c.Sync.AccessToken == "" {
+		return fmt.Errorf("sync.accessToken: is empty")
+	}
+	if c.Sync.Enabled && c.Progress.EpisodesAhead > 0 {
+		// Synced progress would raise the episodes ahead limit on every run, until the whole show is downloaded.
+		return fmt.Errorf("sync.enabled: can't be used with progress.episodesAhead")
+	}
 	return nil
 }
 
@@ -67,6 +141,8 @@ type RSSConfig struct {
 	Qualities        []string          `yaml:"qualities"`
 	PollFrequency    time.Duration     `yaml:"pollFrequency"`
 	CustomParameters map[string]string `yaml:"customParameters"`
+	// TitleMatchThreshold is the minimum similarity, from 0 to 1, for a release to match a show title.
+	TitleMatchThreshold float64 `yaml:"titleMatchThreshold,omitempty"`
 }
 
 func (c *RSSConfig) Validate() error {
@@ -95,6 +171,76 @@ func (t TorrentClientType) Validate() error {
 	return nil
 }
 
+type BatchConfig struct {
+	// ReplaceEpisodes downloads complete batches of aired shows, replacing the downloaded single episodes.
+	ReplaceEpisodes bool `yaml:"replaceEpisodes"`
+	// ReplaceSources only replaces single episodes with batches from the given media sources, like BD.
+	// Empty allows any source.
+	ReplaceSources []string `yaml:"replaceSources,omitempty"`
+	// RemoveReplaced removes single episode torrents once a batch replacing them finishes downloading. Batches filling gaps keep them.
+	// Files are kept unless lifecycle.keepFiles is false.
+	RemoveReplaced bool `yaml:"removeReplaced"`
+	// FillGaps allows partial batches, like E02-07 when E01 and E08 were downloaded, to download episodes missing between downloaded ones.
+	FillGaps bool `yaml:"fillGaps"`
+}
+
+type SeedingLimits struct {
+	// Ratio is the upload ratio limit, like 2.0. Zero uses the torrent client global limit.
+	Ratio float64 `yaml:"ratio,omitempty"`
+	// SeedingTime is the seeding time limit. Zero uses the torrent client global limit.
+	SeedingTime time.Duration `yaml:"seedingTime,omitempty"`
+}
+
+func (l SeedingLimits) Convert() torrentclient.ShareLimits {
+	return torrentclient.ShareLimits{
+		Ratio:       l.Ratio,
+		SeedingTime: l.SeedingTime,
+	}
+}
+
+type LifecycleConfig struct {
+	// SeedingLimits are applied to every torrent of the category.
+	SeedingLimits SeedingLimits `yaml:"seedingLimits,omitempty"`
+	// Shows overrides the seeding limits of shows, by title or id, like anilist:21.
+	Shows map[string]SeedingLimits `yaml:"shows,omitempty"`
+	// RemoveOnLimit removes torrents once they reach their seeding limits.
+	RemoveOnLimit bool `yaml:"removeOnLimit"`
+	// RemoveStatuses removes the torrents of shows with the given list statuses, like completed or dropped.
+	RemoveStatuses []ListStatus `yaml:"removeStatuses,omitempty"`
+	// KeepFiles keeps the downloaded files when removing torrents. Defaults to true.
+	KeepFiles *bool `yaml:"keepFiles,omitempty"`
+}
+
+type DownloadConfig struct {
+	// TopOfQueue adds new torrents to the top of the download queue.
+	TopOfQueue bool `yaml:"topOfQueue,omitempty"`
+	// Sequential downloads the pieces of new torrents in order.
+	Sequential bool `yaml:"sequential,omitempty"`
+	// FirstLastPiece downloads the first and last pieces of new torrents first, allowing previews.
+	FirstLastPiece bool `yaml:"firstLastPiece,omitempty"`
+	// UploadLimit and DownloadLimit are the speed limits of each torrent, in KiB/s. Zero is unlimited.
+	UploadLimit   int64 `yaml:"uploadLimit,omitempty"`
+	DownloadLimit int64 `yaml:"downloadLimit,omitempty"`
+}
+
+func (c DownloadConfig) Validate() error {
+	if c.UploadLimit < 0 {
+		return fmt.Errorf("uploadLimit: must not be negative")
+	}
+	if c.DownloadLimit < 0 {
+		return fmt.Errorf("downloadLimit: must not be negative")
+	}
+	return nil
+}
+
+// Limits converts the speed limits into bytes per second.
+func (c DownloadConfig) Limits() torrentclient.TransferLimits {
+	return torrentclient.TransferLimits{
+		Upload:   c.UploadLimit * 1024,
+		Download: c.DownloadLimit * 1024,
+	}
+}
+
 type TorrentConfig struct {
 	Type             TorrentClientType `yaml:"type"`
 	Host             string            `yaml:"host"`
@@ -104,9 +250,134 @@ type TorrentConfig struct {
 	DownloadPath     string            `yaml:"downloadPath"`
 	CreateShowFolder bool              `yaml:"createShowFolder"`
 	RenameTorrent    *bool             `yaml:"renameTorrent,omitempty"`
+	// CategorySavePath creates the category with the save path, or updates it's save path. Empty leaves it untouched.
+	CategorySavePath string `yaml:"categorySavePath,omitempty"`
+	// CompletedPath moves completed torrents into it, keeping their show folder. Empty disables it.
+	CompletedPath string `yaml:"completedPath,omitempty"`
+	// SkipExtras skips downloading the extras of batches, like NCOP and NCED files.
+	SkipExtras bool `yaml:"skipExtras,omitempty"`
+	// Download configures how new torrents are downloaded, and their speed limits.
+	Download DownloadConfig `yaml:"download,omitempty"`
+	// ReplaceUpgrades removes downloaded torrents once a higher version of the release, like 05v2, finished downloading.
+	// Files are kept unless lifecycle.keepFiles is false.
+	ReplaceUpgrades bool `yaml:"replaceUpgrades,omitempty"`
+	// Batches configures how batches replace, or complete, downloaded single episodes.
+	Batches BatchConfig `yaml:"batches,omitempty"`
+	// StalledTimeout removes torrents stalled for longer than the timeout, blocklisting and replacing their release.
+	// Zero disables it.
+	StalledTimeout time.Duration `yaml:"stalledTimeout,omitempty"`
+	// Lifecycle configures seeding limits and the removal of torrents.
+	Lifecycle LifecycleConfig `yaml:"lifecycle,omitempty"`
 }
 
 func (c TorrentConfig) Validate() error {
+	if err := c.Type.Validate(); err != nil {
+		return fmt.Errorf("type: %w", err)
+	}
+	if c.Host == "" {
+		return fmt.Errorf("host: is empty")
+	}
+	if c.StalledTimeout < 0 {
+		return fmt.Errorf("stalledTimeout: must not be negative")
+	}
+	if c.CategorySavePath != "" && c.Category == "" {
+		return fmt.Errorf("categorySavePath: requires a category")
+	}
+	if err := c.Download.Validate(); err != nil {
+		return fmt.Errorf("download.%w", err)
+	}
+	if err := c.Lifecycle.Validate(); err != nil {
+		return fmt.Errorf("lifecycle.%w", err)
+	}
+	return nil
+}
+
+func (c LifecycleConfig) Validate() error {
+	if err := c.SeedingLimits.Validate(); err != nil {
+		return fmt.Errorf("seedingLimits.%w", err)
+	}
+	for show, limits := range c.Shows {
+		if err := limits.Validate(); err != nil {
+			return fmt.Errorf("shows[%s].%w", show, err)
+		}
+	}
+	for i, status := range c.RemoveStatuses {
+		if err := status.Validate(); err != nil {
+			return fmt.Errorf("removeStatuses[%d]: %w", i, err)
+		}
+		if status == "all" {
+			return fmt.Errorf("removeStatuses[%d]: 'all' would remove every torrent", i)
+		}
+	}
+	return nil
+}
+
+func (l SeedingLimits) Validate() error {
+	if l.Ratio < 0 {
+		return fmt.Errorf("ratio: must not be negative")
+	}
+	if l.SeedingTime < 0 {
+		return fmt.Errorf("seedingTime: must not be negative")
+	}
+	return nil
+}
+
+type AnimeDBConfig struct {
+	// Paths are offline anime mapping datasets, like manami-project anime-offline-database or Fribb anime-lists.
+	Paths []string `yaml:"paths"`
+}
+
+type LibraryConfig struct {
+	// Path is the media library completed torrents are imported into, like /media/anime. Empty disables it.
+	Path string `yaml:"path"`
+	// Mode is how video files are placed into the library: hardlink, copy or move. Defaults to hardlink.
+	Mode library.Mode `yaml:"mode,omitempty"`
+	// DownloadPath is the torrentConfig.downloadPath as seen by Animeman, when it runs with a different mount.
+	DownloadPath string `yaml:"downloadPath,omitempty"`
+}
+
+func (c *LibraryConfig) Validate() error {
+	if c.Path == "" {
+		return nil
+	}
+	if c.Mode == "" {
+		c.Mode = library.ModeHardlink
+	}
+	if err := c.Mode.Validate(); err != nil {
+		return fmt.Errorf("mode: %w", err)
+	}
+	return nil
+}
+
+type MediaServerType string
+
+const (
+	MediaServerTypeJellyfin MediaServerType = "jellyfin"
+	MediaServerTypeEmby     MediaServerType = "emby"
+	MediaServerTypePlex     MediaServerType = "plex"
+)
+
+func (t MediaServerType) Validate() error {
+	if t != MediaServerTypeJellyfin && t != MediaServerTypeEmby && t != MediaServerTypePlex {
+		return fmt.Errorf("'%s' is invalid. should be [jellyfin,emby,plex]", t)
+	}
+	return nil
+}
+
+type MediaServerConfig struct {
+	// Type is the media server refreshed once episodes finish downloading. Empty disables it.
+	Type MediaServerType `yaml:"type"`
+	Host string          `yaml:"host"`
+	// Token is the Jellyfin or Emby API key, or the Plex token.
+	Token string `yaml:"token"`
+	// SectionID is the Plex library section of the shows. Empty finds the section containing the show folder.
+	SectionID string `yaml:"sectionID,omitempty"`
+}
+
+func (c MediaServerConfig) Validate() error {
+	if c.Type == "" {
+		return nil
+	}
 	if err := c.Type.Validate(); err != nil {
 		return fmt.Errorf("type: %w", err)
 	}
@@ -129,7 +400,14 @@ type Config struct {
 	AnimeListConfig `yaml:"animeList"`
 	RSSConfig       `yaml:"rssConfig"`
 	TorrentConfig   `yaml:"torrentConfig"`
-	LogLevel        LogLevel `yaml:"logLevel"`
+	AnimeDB         AnimeDBConfig     `yaml:"animeDatabase,omitempty"`
+	Library         LibraryConfig     `yaml:"library,omitempty"`
+	MediaServer     MediaServerConfig `yaml:"mediaServer,omitempty"`
+	LogLevel        LogLevel          `yaml:"logLevel"`
+	// DataDir is where Animeman persists it's state. Defaults to the config directory.
+	DataDir string `yaml:"dataDir,omitempty"`
+	// Aliases maps anime list titles, or ids like anilist:21, to extra search and match terms.
+	Aliases map[string][]string `yaml:"aliases,omitempty"`
 }
 
 func (l LogLevel) Convert() zerolog.Level {
@@ -155,6 +433,17 @@ func (c *Config) Validate() error {
 	if err := c.TorrentConfig.Validate(); err != nil {
 		return fmt.Errorf("torrentConfig.%w", err)
 	}
+	if err := c.Library.Validate(); err != nil {
+		return fmt.Errorf("library.%w", err)
+	}
+	if err := c.MediaServer.Validate(); err != nil {
+		return fmt.Errorf("mediaServer.%w", err)
+	}
+	for i, status := range c.Lifecycle.RemoveStatuses {
+		if slices.Contains(c.ListStatuses, status) || slices.Contains(c.ListStatuses, "all") {
+			return fmt.Errorf("torrentConfig.lifecycle.removeStatuses[%d]: '%s' is also downloaded by animeList.listStatuses", i, status)
+		}
+	}
 	return nil
 }
 
@@ -165,9 +454,13 @@ func GenerateBoilerplateConfig() {
 	}
 	err = yaml.NewEncoder(file).Encode(Config{
 		AnimeListConfig: AnimeListConfig{
-			Type:     AnimeListTypeMAL,
-			Username: "YOUR_USERNAME",
-			CacheTTL: 30 * time.Minute,
+			Type:         AnimeListTypeMAL,
+			Username:     "YOUR_USERNAME",
+			CacheTTL:     30 * time.Minute,
+			ListStatuses: []ListStatus{"watching"},
+			PlanToWatch: PlanToWatchConfig{
+				AiringWithin: 7 * 24 * time.Hour,
+			},
 		},
 		RSSConfig: RSSConfig{
 			SearchSuffix:  `-"dub"`,
@@ -191,6 +484,31 @@ func GenerateBoilerplateConfig() {
 	}
 }
 
+// ReadEpisodeMappings reads a yaml file mapping anime list titles to the episode count of each season.
+// Example: `Show Season 2: [12, 13]`.
+func ReadEpisodeMappings(path string) (map[string][]int, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening episode mappings: %w", err)
+	}
+	defer file.Close()
+	var mappings map[string][]int
+	if err := yaml.NewDecoder(file).Decode(&mappings); err != nil {
+		return nil, fmt.Errorf("reading episode mappings: %w", err)
+	}
+	for title, seasons := range mappings {
+		for _, count := range seasons {
+			if count < 0 {
+				return nil, fmt.Errorf("episode mappings: %s: episode count must not be negative", title)
+			}
+		}
+	}
+	return mappings, nil
+}
+
 func ReadConfig(path string) (Config, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -201,5 +519,8 @@ func ReadConfig(path string) (Config, error) {
 	if err = yaml.NewDecoder(file).Decode(&config); err != nil {
 		log.Fatal().Msgf("could not read config.yaml: %s", err)
 	}
+	if config.DataDir == "" {
+		config.DataDir = filepath.Dir(path)
+	}
 	return config, config.Validate()
 }
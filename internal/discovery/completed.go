@@ -0,0 +1,285 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/library"
+	"github.com/sonalys/animeman/internal/parser"
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// libraryFile is a torrent file, and it's destination in the media library.
+type libraryFile struct {
+	// Source is the file path, relative to the torrent save path.
+	Source      string
+	Destination string
+}
+
+// hasCompletionWatcher returns true when completed torrents are handled, like imported into the media library.
+func (c *Controller) hasCompletionWatcher() bool {
+	return c.dep.ProcessedStore != nil &&
+		(c.dep.Config.LibraryPath != "" || c.dep.Config.CompletedPath != "" || c.dep.MediaServer != nil)
+}
+
+// localPath returns the path of a torrent file as seen by Animeman.
+// The torrent client download path is replaced by LibraryDownloadPath, when configured.
+func (c Config) localPath(savePath, name string) string {
+	filePath := filepath.Join(savePath, name)
+	if c.LibraryDownloadPath == "" || c.DownloadPath == "" {
+		return filePath
+	}
+
+	relative, err := filepath.Rel(c.DownloadPath, filePath)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return filePath
+	}
+
+	return filepath.Join(c.LibraryDownloadPath, relative)
+}
+
+// largestVideo returns the largest video file, ignoring other files like subtitles.
+func largestVideo(files []torrentclient.File) (torrentclient.File, bool) {
+	var (
+		largest torrentclient.File
+		found   bool
+	)
+
+	for _, file := range files {
+		if parser.ParseContainer(file.Name) == "" {
+			continue
+		}
+		if !found || file.Size > largest.Size {
+			largest = file
+			found = true
+		}
+	}
+
+	return largest, found
+}
+
+// libraryFiles returns where the video files of a torrent go into the library.
+// Movies, single releases and single episodes use their largest video file, batches parse the episode of each video file.
+// Files without episodes in batches, like extras, are ignored.
+func libraryFiles(entry animelist.Entry, root string, tag tags.Tag, files []torrentclient.File) []libraryFile {
+	title := selectIdealTitle(entry.Titles)
+
+	if isSingleRelease(entry) || !tag.IsMultiEpisode() {
+		file, ok := largestVideo(files)
+		if !ok {
+			return nil
+		}
+
+		destination := library.EpisodePath(root, title, tag, parser.ParseContainer(file.Name))
+		if isSingleRelease(entry) {
+			destination = library.MoviePath(root, title, parser.ParseContainer(file.Name))
+		}
+
+		return []libraryFile{{Source: file.Name, Destination: destination}}
+	}
+
+	mapping := episodeMapping(entry)
+	out := make([]libraryFile, 0, len(files))
+
+	for _, file := range files {
+		container := parser.ParseContainer(file.Name)
+		if container == "" {
+			continue
+		}
+
+		fileTag := mapping.ToSeasonal(parser.Parse(path.Base(file.Name), tag.FirstSeason()).Tag)
+		if len(fileTag.Episodes) == 0 {
+			continue
+		}
+
+		if entry.Format == animelist.MediaFormatSpecial || len(fileTag.Seasons) == 0 {
+			fileTag.Seasons = []int{tag.FirstSeason()}
+		}
+
+		out = append(out, libraryFile{
+			Source:      file.Name,
+			Destination: library.EpisodePath(root, title, fileTag, container),
+		})
+	}
+
+	return out
+}
+
+// ProcessCompleted handles the completed torrents of each entry once, importing their video files into the media library.
+// Torrents are then moved into the completed path, and the media server is refreshed for each entry with completed torrents.
+// Torrents failing to import, or to move, are retried on the next run.
+func (c *Controller) ProcessCompleted(ctx context.Context, entries []animelist.Entry) error {
+	for _, entry := range entries {
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		completed := false
+
+		for _, torrent := range torrents {
+			if torrent.Progress < 1 || c.dep.ProcessedStore.IsProcessed(torrent.Hash) {
+				continue
+			}
+
+			if err := c.importTorrent(ctx, entry, torrent); err != nil {
+				logger.
+					Error().
+					Str("hash", torrent.Hash).
+					Msgf("could not import torrent into the library: %s", err)
+				continue
+			}
+
+			if err := c.moveCompleted(ctx, entry, torrent); err != nil {
+				logger.
+					Error().
+					Str("hash", torrent.Hash).
+					Msgf("could not move completed torrent: %s", err)
+				continue
+			}
+
+			if err := c.dep.ProcessedStore.MarkProcessed(torrent.Hash); err != nil {
+				return fmt.Errorf("marking torrent as processed: %w", err)
+			}
+
+			completed = true
+		}
+
+		if completed {
+			c.refreshMediaServer(ctx, entry)
+		}
+	}
+
+	return nil
+}
+
+// showPath returns the folder of an entry refreshed by the media server.
+// It's the show folder in the media library, when configured, or where it's completed torrents are.
+func (c *Controller) showPath(entry animelist.Entry) string {
+	title := selectIdealTitle(entry.Titles)
+	switch {
+	case c.dep.Config.LibraryPath != "":
+		return filepath.Join(c.dep.Config.LibraryPath, library.SanitizeName(title))
+	case c.dep.Config.CompletedPath != "":
+		return c.TorrentGetCompletedPath(title)
+	default:
+		return c.TorrentGetDownloadPath(title)
+	}
+}
+
+// moveCompleted moves a completed torrent into the completed path, when configured.
+func (c *Controller) moveCompleted(ctx context.Context, entry animelist.Entry, torrent torrentclient.Torrent) error {
+	if c.dep.Config.CompletedPath == "" {
+		return nil
+	}
+
+	logger := getLogger(ctx)
+	location := c.TorrentGetCompletedPath(selectIdealTitle(entry.Titles))
+	if filepath.Clean(torrent.SavePath) == filepath.Clean(location) {
+		return nil
+	}
+
+	if err := c.dep.TorrentClient.SetLocation(ctx, []string{torrent.Hash}, location); err != nil {
+		return fmt.Errorf("setting torrent location: %w", err)
+	}
+
+	logger.
+		Info().
+		Str("hash", torrent.Hash).
+		Str("location", location).
+		Msg("moved completed torrent")
+
+	return nil
+}
+
+// refreshMediaServer scans the show folder of an entry into the media server library.
+// Failures are only logged, since the media server also scans it's libraries periodically.
+func (c *Controller) refreshMediaServer(ctx context.Context, entry animelist.Entry) {
+	if c.dep.MediaServer == nil {
+		return
+	}
+
+	logger := getLogger(ctx)
+	path := c.showPath(entry)
+
+	if err := c.dep.MediaServer.RefreshPath(ctx, path); err != nil {
+		logger.
+			Error().
+			Str("path", path).
+			Msgf("could not refresh media server: %s", err)
+		return
+	}
+
+	logger.
+		Debug().
+		Str("path", path).
+		Msg("refreshed media server")
+}
+
+// importTorrent places the video files of a completed torrent into the media library, when configured.
+// Files already in the library are kept, unless the torrent has a higher release version, like 05v2 replacing 05.
+// Files imported before their version was tracked are considered first releases.
+func (c *Controller) importTorrent(ctx context.Context, entry animelist.Entry, torrent torrentclient.Torrent) error {
+	if c.dep.Config.LibraryPath == "" {
+		return nil
+	}
+
+	logger := getLogger(ctx)
+
+	files, err := c.dep.TorrentClient.ListFiles(ctx, torrent.Hash)
+	if err != nil {
+		return fmt.Errorf("listing torrent files: %w", err)
+	}
+
+	record := tags.Decode(torrent.Tags)
+	tag := episodeMapping(entry).ToSeasonal(record.Episode)
+	version := releaseVersion(record.Version)
+
+	for _, file := range libraryFiles(entry, c.dep.Config.LibraryPath, tag, files) {
+		source := c.dep.Config.localPath(torrent.SavePath, file.Source)
+
+		err := library.Place(c.dep.Config.LibraryMode, source, file.Destination)
+		if errors.Is(err, library.ErrExists) {
+			imported, _ := c.dep.ProcessedStore.ImportedVersion(file.Destination)
+			if version <= releaseVersion(imported) {
+				logger.
+					Debug().
+					Str("destination", file.Destination).
+					Msg("file already in the library")
+				continue
+			}
+
+			err = library.Replace(c.dep.Config.LibraryMode, source, file.Destination)
+		}
+		if err != nil {
+			return fmt.Errorf("placing %s: %w", file.Source, err)
+		}
+
+		if err := c.dep.ProcessedStore.MarkImported(file.Destination, version); err != nil {
+			return fmt.Errorf("marking file as imported: %w", err)
+		}
+
+		logger.
+			Info().
+			Str("source", source).
+			Str("destination", file.Destination).
+			Int("version", version).
+			Msg("imported file into the library")
+	}
+
+	return nil
+}
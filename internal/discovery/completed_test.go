@@ -0,0 +1,214 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonalys/animeman/internal/library"
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_libraryFiles(t *testing.T) {
+	show := animelist.Entry{Titles: []string{"Show", "ショー"}, Format: animelist.MediaFormatTV}
+
+	tests := []struct {
+		name  string
+		entry animelist.Entry
+		tag   tags.Tag
+		files []torrentclient.File
+		want  []libraryFile
+	}{
+		{
+			name:  "episode",
+			entry: show,
+			tag:   tags.SeasonEpisode(1, 5),
+			files: []torrentclient.File{
+				{Name: "[Group] Show - 05 [1080p].ass", Size: 10},
+				{Name: "[Group] Show - 05 [1080p].mkv", Size: 100},
+			},
+			want: []libraryFile{
+				{Source: "[Group] Show - 05 [1080p].mkv", Destination: "/media/Show/Season 01/Show - S01E05.mkv"},
+			},
+		},
+		{
+			name:  "batch",
+			entry: show,
+			tag:   tags.Tag{Seasons: []int{2}, Episodes: []float64{1, 2}},
+			files: []torrentclient.File{
+				{Name: "Show S2/[Group] Show S2 - 01.mkv", Size: 100},
+				{Name: "Show S2/[Group] Show S2 - 02.mkv", Size: 100},
+				{Name: "Show S2/Extras/[Group] Show S2 - NCOP.mkv", Size: 100},
+			},
+			want: []libraryFile{
+				{Source: "Show S2/[Group] Show S2 - 01.mkv", Destination: "/media/Show/Season 02/Show - S02E01.mkv"},
+				{Source: "Show S2/[Group] Show S2 - 02.mkv", Destination: "/media/Show/Season 02/Show - S02E02.mkv"},
+			},
+		},
+		{
+			name:  "movie",
+			entry: animelist.Entry{Titles: []string{"Movie: Subtitle"}, Format: animelist.MediaFormatMovie},
+			files: []torrentclient.File{
+				{Name: "Movie/sample.mkv", Size: 10},
+				{Name: "Movie/Movie.mkv", Size: 100},
+			},
+			want: []libraryFile{
+				{Source: "Movie/Movie.mkv", Destination: "/media/Movie - Subtitle/Movie - Subtitle.mkv"},
+			},
+		},
+		{
+			name:  "no video",
+			entry: show,
+			tag:   tags.SeasonEpisode(1, 5),
+			files: []torrentclient.File{{Name: "readme.txt"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, libraryFiles(tt.entry, "/media", tt.tag, tt.files))
+		})
+	}
+}
+
+func Test_localPath(t *testing.T) {
+	config := Config{DownloadPath: "/downloads/animes", LibraryDownloadPath: "/mnt/animes"}
+	require.Equal(t, "/mnt/animes/Show/05.mkv", config.localPath("/downloads/animes/Show", "05.mkv"))
+	require.Equal(t, "/other/05.mkv", config.localPath("/other", "05.mkv"))
+	require.Equal(t, "/downloads/animes/05.mkv", Config{}.localPath("/downloads/animes", "05.mkv"))
+}
+
+func Test_ProcessCompleted(t *testing.T) {
+	dir := t.TempDir()
+	downloads := filepath.Join(dir, "downloads")
+	media := filepath.Join(dir, "media")
+
+	require.NoError(t, os.MkdirAll(downloads, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloads, "Show - 05.mkv"), []byte("video"), 0o644))
+
+	torrentClient := &fakeTorrentClient{
+		torrents: []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1, SavePath: downloads},
+			{Hash: "b", Tags: []string{"!show", "am:ep:S01E06"}, Progress: 0.5, SavePath: downloads},
+		},
+		files: map[string][]torrentclient.File{
+			"a": {{Name: "Show - 05.mkv", Size: 5}},
+		},
+	}
+	processed := &fakeProcessedStore{}
+
+	c := New(Dependencies{
+		TorrentClient:  torrentClient,
+		ProcessedStore: processed,
+		Config:         Config{LibraryPath: media, LibraryMode: library.ModeHardlink},
+	})
+
+	entries := []animelist.Entry{{Titles: []string{"Show"}}}
+	require.NoError(t, c.ProcessCompleted(t.Context(), entries))
+
+	content, err := os.ReadFile(filepath.Join(media, "Show", "Season 01", "Show - S01E05.mkv"))
+	require.NoError(t, err)
+	require.Equal(t, "video", string(content))
+	require.Equal(t, map[string]struct{}{"a": {}}, processed.hashes)
+
+	// Processed torrents are only handled once.
+	require.NoError(t, os.RemoveAll(media))
+	require.NoError(t, c.ProcessCompleted(t.Context(), entries))
+	require.NoDirExists(t, media)
+}
+
+func Test_ProcessCompleted_upgrade(t *testing.T) {
+	dir := t.TempDir()
+	downloads := filepath.Join(dir, "downloads")
+	media := filepath.Join(dir, "media")
+	destination := filepath.Join(media, "Show", "Season 01", "Show - S01E05.mkv")
+
+	require.NoError(t, os.MkdirAll(downloads, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(destination), 0o755))
+	require.NoError(t, os.WriteFile(destination, []byte("imported"), 0o644))
+	for name, content := range map[string]string{
+		"[A] Show - 05.mkv":   "v1",
+		"[A] Show - 05v2.mkv": "v2",
+		"[B] Show - 05.mkv":   "other v1",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(downloads, name), []byte(content), 0o644))
+	}
+
+	torrentClient := &fakeTorrentClient{
+		torrents: []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1, SavePath: downloads},
+			{Hash: "b", Tags: []string{"!show", "am:ep:S01E05", "am:v:2"}, Progress: 1, SavePath: downloads},
+			{Hash: "c", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1, SavePath: downloads},
+		},
+		files: map[string][]torrentclient.File{
+			"a": {{Name: "[A] Show - 05.mkv", Size: 5}},
+			"b": {{Name: "[A] Show - 05v2.mkv", Size: 5}},
+			"c": {{Name: "[B] Show - 05.mkv", Size: 5}},
+		},
+	}
+	processed := &fakeProcessedStore{}
+
+	c := New(Dependencies{
+		TorrentClient:  torrentClient,
+		ProcessedStore: processed,
+		Config:         Config{LibraryPath: media, LibraryMode: library.ModeCopy},
+	})
+
+	// Files imported before tracking their version are first releases, only replaced by higher versions.
+	require.NoError(t, c.ProcessCompleted(t.Context(), []animelist.Entry{{Titles: []string{"Show"}}}))
+
+	content, err := os.ReadFile(destination)
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(content))
+	require.Equal(t, map[string]int{destination: 2}, processed.files)
+}
+
+func Test_ProcessCompleted_mediaServer(t *testing.T) {
+	torrentClient := &fakeTorrentClient{
+		torrents: []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1},
+			{Hash: "b", Tags: []string{"!other", "am:ep:S01E01"}, Progress: 0.5},
+		},
+	}
+	mediaServer := &fakeMediaServer{}
+
+	c := New(Dependencies{
+		TorrentClient:  torrentClient,
+		ProcessedStore: &fakeProcessedStore{},
+		MediaServer:    mediaServer,
+		Config:         Config{DownloadPath: "/downloads", CreateShowFolder: true},
+	})
+	require.True(t, c.hasCompletionWatcher())
+
+	entries := []animelist.Entry{{Titles: []string{"Show"}}, {Titles: []string{"Other"}}}
+	require.NoError(t, c.ProcessCompleted(t.Context(), entries))
+	require.Equal(t, []string{"/downloads/Show"}, mediaServer.refreshed)
+
+	// Shows are only refreshed when new torrents complete.
+	require.NoError(t, c.ProcessCompleted(t.Context(), entries))
+	require.Equal(t, []string{"/downloads/Show"}, mediaServer.refreshed)
+}
+
+func Test_ProcessCompleted_completedPath(t *testing.T) {
+	torrentClient := &fakeTorrentClient{
+		torrents: []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1, SavePath: "/downloads/Show"},
+			{Hash: "b", Tags: []string{"!show", "am:ep:S01E06"}, Progress: 0.5, SavePath: "/downloads/Show"},
+		},
+	}
+	mediaServer := &fakeMediaServer{}
+
+	c := New(Dependencies{
+		TorrentClient:  torrentClient,
+		ProcessedStore: &fakeProcessedStore{},
+		MediaServer:    mediaServer,
+		Config:         Config{DownloadPath: "/downloads", CompletedPath: "/completed", CreateShowFolder: true},
+	})
+
+	require.NoError(t, c.ProcessCompleted(t.Context(), []animelist.Entry{{Titles: []string{"Show"}}}))
+	require.Equal(t, "/completed/Show", torrentClient.torrents[0].SavePath)
+	require.Equal(t, "/downloads/Show", torrentClient.torrents[1].SavePath)
+	require.Equal(t, []string{"/completed/Show"}, mediaServer.refreshed)
+}
@@ -2,9 +2,75 @@ package discovery
 
 import (
 	"time"
+
+	"github.com/sonalys/animeman/internal/library"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 )
 
 type Config struct {
+	ListStatuses            []animelist.ListStatus
+	PlanToWatchAiringWithin time.Duration
+	PlanToWatchBatchOnly    bool
+	// EpisodesAhead limits downloads to the list progress plus the given number of episodes.
+	// Zero disables the limit.
+	EpisodesAhead int
+	// SkipWatched avoids downloading episodes already marked as watched in the anime list.
+	SkipWatched bool
+	// EpisodeMappings maps anime list titles to the episode count of each season.
+	// It's used for converting absolute episode numbers into season episode numbers.
+	EpisodeMappings map[string][]int
+	// TitleMatchThreshold is the minimum score, from 0 to 1, for a release to match an entry title.
+	// Zero uses matcher.DefaultThreshold.
+	TitleMatchThreshold float64
+	// ReplaceUpgrades removes downloaded torrents once a higher version of the release, like 05v2, finished downloading.
+	// Files are kept when RemoveKeepFiles is set.
+	ReplaceUpgrades bool
+	// BatchReplaceEpisodes downloads complete batches of aired shows, replacing the downloaded single episodes.
+	BatchReplaceEpisodes bool
+	// BatchReplaceSources limits replacement batches to the given media sources, like BD. Empty allows any source.
+	BatchReplaceSources []string
+	// BatchRemoveReplaced removes single episode torrents once a batch replacing them finishes downloading. Batches filling gaps keep them.
+	// Files are kept when RemoveKeepFiles is set.
+	BatchRemoveReplaced bool
+	// BatchFillGaps allows partial batches, like E02-07 when E01 and E08 were downloaded, to download episodes missing between downloaded ones.
+	BatchFillGaps bool
+	// SeedingLimits are applied to every torrent of the category. Zero values use the torrent client global limits.
+	SeedingLimits torrentclient.ShareLimits
+	// ShowSeedingLimits overrides the seeding limits of shows, by title or identity key like anilist:21.
+	ShowSeedingLimits map[string]torrentclient.ShareLimits
+	// RemoveOnSeedingLimit removes torrents once they reach their seeding limits.
+	RemoveOnSeedingLimit bool
+	// RemoveListStatuses removes the torrents of shows with the given list statuses, like completed or dropped.
+	RemoveListStatuses []animelist.ListStatus
+	// RemoveKeepFiles keeps the downloaded files when removing torrents by their lifecycle, upgrades or replacement batches.
+	RemoveKeepFiles bool
+	// StalledTimeout removes torrents stalled for longer than the timeout, downloading the next best release instead.
+	// Zero disables it.
+	StalledTimeout time.Duration
+	// CategorySavePath creates the category with the save path, or updates it's save path. Empty leaves it untouched.
+	CategorySavePath string
+	// CompletedPath moves completed torrents into it, keeping their show folder. Empty disables it.
+	CompletedPath string
+	// SkipExtras skips downloading the extras of batches, like NCOP and NCED files.
+	SkipExtras bool
+	// AddToTopOfQueue, SequentialDownload and FirstLastPiecePriority configure how new torrents are downloaded.
+	AddToTopOfQueue        bool
+	SequentialDownload     bool
+	FirstLastPiecePriority bool
+	// TransferLimits are the speed limits of every torrent of the category. Zero is unlimited.
+	TransferLimits torrentclient.TransferLimits
+	// LibraryPath is the media library completed torrents are imported into. Empty disables it.
+	LibraryPath string
+	// LibraryMode is how video files are placed into the library.
+	LibraryMode library.Mode
+	// LibraryDownloadPath is the DownloadPath as seen by Animeman, when it differs from the torrent client.
+	LibraryDownloadPath string
+	// Aliases maps anime list titles, or identity keys like anilist:21, to extra search and match terms.
+	Aliases map[string][]string
+	// SyncProgress updates the anime list progress once episodes finish downloading.
+	SyncProgress bool
+
 	SearchSuffix     string
 	Sources          []string
 	Qualitites       []string
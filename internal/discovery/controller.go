@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/animedb"
 	"github.com/sonalys/animeman/internal/integrations/nyaa"
 )
 
@@ -12,20 +13,39 @@ type (
 	Dependencies struct {
 		NYAA            *nyaa.API
 		AnimeListClient AnimeListSource
+		// AnimeListWriter is optional, only used when progress sync is enabled.
+		AnimeListWriter AnimeListWriter
 		TorrentClient   TorrentClient
-		Config          Config
+		// AnimeDB is optional, used for resolving identifiers and synonyms across providers.
+		AnimeDB *animedb.DB
+		// ShowStore is optional, used for keeping the episode history of renamed shows.
+		ShowStore ShowStore
+		// ProcessedStore is required for handling completed torrents, like importing them into the media library.
+		ProcessedStore ProcessedStore
+		// OwnedStore is optional, used for skipping the episodes imported from disk.
+		OwnedStore OwnedStore
+		// MediaServer is optional, refreshed once the torrents of a show complete.
+		MediaServer MediaServer
+		// Blocklist is optional, used for skipping the releases removed by the stalled torrent watchdog.
+		Blocklist Blocklist
+		Config    Config
 	}
 
 	Controller struct {
 		dep             Dependencies
 		intervalTracker *IntervalTracker
+		torrents        *torrentCache
 	}
 )
 
 func New(dep Dependencies) *Controller {
+	torrents := newTorrentCache(dep.TorrentClient, dep.Config.Category)
+	dep.TorrentClient = torrents
+
 	return &Controller{
 		dep:             dep,
 		intervalTracker: NewIntervalTracker(dep.Config.PollFrequency),
+		torrents:        torrents,
 	}
 }
 
@@ -3,18 +3,64 @@ package discovery
 import (
 	"context"
 
+	"github.com/sonalys/animeman/internal/tags"
 	"github.com/sonalys/animeman/pkg/v1/animelist"
 	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 )
 
 type (
 	AnimeListSource interface {
-		GetCurrentlyWatching(ctx context.Context) ([]animelist.Entry, error)
+		GetAnimeList(ctx context.Context, statuses ...animelist.ListStatus) ([]animelist.Entry, error)
+	}
+
+	// AnimeListWriter updates anime list entries, used for syncing progress back to the anime list.
+	AnimeListWriter interface {
+		UpdateProgress(ctx context.Context, entry animelist.Entry, progress int, status animelist.ListStatus) error
 	}
 
 	TorrentClient interface {
 		List(ctx context.Context, arg *torrentclient.ListTorrentConfig) ([]torrentclient.Torrent, error)
 		AddTorrent(ctx context.Context, arg *torrentclient.AddTorrentConfig) error
 		AddTorrentTags(ctx context.Context, hashes []string, tags []string) error
+		RemoveTorrentTags(ctx context.Context, hashes []string, tags []string) error
+		DeleteTorrents(ctx context.Context, hashes []string, deleteFiles bool) error
+		SetShareLimits(ctx context.Context, hashes []string, limits torrentclient.ShareLimits) error
+		ListFiles(ctx context.Context, hash string) ([]torrentclient.File, error)
+		SetFilePriority(ctx context.Context, hash string, indexes []int, priority torrentclient.FilePriority) error
+		SetLocation(ctx context.Context, hashes []string, location string) error
+		SetTransferLimits(ctx context.Context, hashes []string, limits torrentclient.TransferLimits) error
+		EnsureCategory(ctx context.Context, name, savePath string) error
+	}
+
+	// Blocklist persists the info hashes of dead releases, so they are not downloaded again.
+	Blocklist interface {
+		IsBlocked(hash string) bool
+		Block(hash, reason string) error
+	}
+
+	// ProcessedStore persists the hashes of completed torrents already handled, so they are only handled once.
+	// It also persists the release version of each file imported into the media library, so upgrades replace them.
+	ProcessedStore interface {
+		IsProcessed(hash string) bool
+		MarkProcessed(hash string) error
+		ImportedVersion(destination string) (int, bool)
+		MarkImported(destination string, version int) error
+	}
+
+	// MediaServer scans new files into the media server library, like Jellyfin or Plex.
+	MediaServer interface {
+		RefreshPath(ctx context.Context, path string) error
+	}
+
+	// OwnedStore persists the episodes of each show found on disk without torrents, keyed by it's identity.
+	OwnedStore interface {
+		OwnedEpisodes(key string) []tags.Tag
+		AddOwnedEpisodes(key string, episodes ...tags.Tag) error
+	}
+
+	// ShowStore persists the torrent title tag of each show, keyed by it's identity.
+	ShowStore interface {
+		GetTitleTag(key string) (string, bool)
+		SetTitleTag(key, tag string) error
 	}
 )
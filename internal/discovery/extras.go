@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+var (
+	// extraFileExpr matches the file names of extras, like Show - NCOP1.mkv, Show - NC ED.mkv or Show - PV2.mkv.
+	// Words also used by titles, like Trailer, only match at the end of the name, like Show - Trailer 2 [1080p].mkv.
+	extraFileExpr = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:(?:nc[\s_]?(?:op|ed)\d*|creditless|(?:op|ed|pv|cm)\d+)(?:$|[^a-z0-9])|(?:menu|preview|trailer)s?(?:[\s_]*\d+)?\s*(?:$|[\[(]))`)
+	// extraFolderExpr matches the folders of extras, like Show/Extras/Show - Interview.mkv.
+	extraFolderExpr = regexp.MustCompile(`(?i)^(?:extras?|bonus|features|menus?|scans|nc|creditless|ncop|nced)$`)
+)
+
+// isExtraFile returns true for extras, like creditless openings and endings, by their file name or folder.
+func isExtraFile(name string) bool {
+	if extraFileExpr.MatchString(strings.TrimSuffix(path.Base(name), path.Ext(name))) {
+		return true
+	}
+
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if extraFolderExpr.MatchString(path.Base(dir)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SkipExtras skips downloading the extras of each entry downloading batch.
+// Torrents still fetching their metadata have no files, so they are skipped on the next run.
+func (c *Controller) SkipExtras(ctx context.Context, entries []animelist.Entry) error {
+	for _, entry := range entries {
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		for _, torrent := range torrents {
+			if torrent.Progress >= 1 || !torrentTag(torrent).IsMultiEpisode() {
+				continue
+			}
+
+			files, err := c.dep.TorrentClient.ListFiles(ctx, torrent.Hash)
+			if err != nil {
+				return fmt.Errorf("listing torrent files: %w", err)
+			}
+
+			var indexes []int
+			for _, file := range files {
+				if file.Priority != torrentclient.FilePrioritySkip && isExtraFile(file.Name) {
+					indexes = append(indexes, file.Index)
+				}
+			}
+
+			if len(indexes) == 0 {
+				continue
+			}
+
+			if err := c.dep.TorrentClient.SetFilePriority(ctx, torrent.Hash, indexes, torrentclient.FilePrioritySkip); err != nil {
+				return fmt.Errorf("skipping extras: %w", err)
+			}
+
+			logger.
+				Info().
+				Str("hash", torrent.Hash).
+				Int("files", len(indexes)).
+				Msg("skipped downloading extras")
+		}
+	}
+
+	return nil
+}
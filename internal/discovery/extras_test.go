@@ -0,0 +1,58 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isExtraFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "Show/[Group] Show - 01 [1080p].mkv", want: false},
+		{name: "Show/[Group] Show - NCOP1 [1080p].mkv", want: true},
+		{name: "Show/[Group] Show - NC ED [1080p].mkv", want: true},
+		{name: "Show/[Group] Show - Creditless Opening.mkv", want: true},
+		{name: "Show/[Group] Show - PV2.mkv", want: true},
+		{name: "Show/Extras/[Group] Show - Interview.mkv", want: true},
+		{name: "Show/NC/[Group] Show - 01.mkv", want: true},
+		{name: "Show/[Group] Show - Trailer 2 [1080p].mkv", want: true},
+		{name: "Trailer Park/Trailer Park - 01.mkv", want: false},
+		{name: "Ed Show - 01.mkv", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isExtraFile(tt.name))
+		})
+	}
+}
+
+func Test_SkipExtras(t *testing.T) {
+	torrentClient := &fakeTorrentClient{
+		torrents: []torrentclient.Torrent{
+			{Hash: "batch", Tags: []string{"!show", "am:ep:S01E01-12"}, Progress: 0.5},
+			{Hash: "episode", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 0.5},
+		},
+		files: map[string][]torrentclient.File{
+			"batch": {
+				{Index: 0, Name: "Show/Show - 01.mkv", Priority: torrentclient.FilePriorityNormal},
+				{Index: 1, Name: "Show/Show - NCOP.mkv", Priority: torrentclient.FilePriorityNormal},
+			},
+			"episode": {
+				{Index: 0, Name: "Show - PV1.mkv", Priority: torrentclient.FilePriorityNormal},
+			},
+		},
+	}
+
+	c := New(Dependencies{TorrentClient: torrentClient, Config: Config{SkipExtras: true}})
+	require.NoError(t, c.SkipExtras(t.Context(), []animelist.Entry{{Titles: []string{"Show"}}}))
+
+	require.Equal(t, torrentclient.FilePriorityNormal, torrentClient.files["batch"][0].Priority)
+	require.Equal(t, torrentclient.FilePrioritySkip, torrentClient.files["batch"][1].Priority)
+	// Single episodes are left untouched.
+	require.Equal(t, torrentclient.FilePriorityNormal, torrentClient.files["episode"][0].Priority)
+}
@@ -0,0 +1,257 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// fakeTorrentClient is an in-memory TorrentClient, filtering torrents like qBittorrent does.
+type fakeTorrentClient struct {
+	torrents []torrentclient.Torrent
+	added    []*torrentclient.AddTorrentConfig
+	deleted  []string
+	limits   map[string]torrentclient.ShareLimits
+	files    map[string][]torrentclient.File
+	// categories maps the ensured categories to their save path.
+	categories map[string]string
+	// deleteFiles is the deleteFiles argument of the last deletion.
+	deleteFiles bool
+	// lists counts the calls to List.
+	lists int
+}
+
+func (f *fakeTorrentClient) List(_ context.Context, arg *torrentclient.ListTorrentConfig) ([]torrentclient.Torrent, error) {
+	f.lists++
+	out := make([]torrentclient.Torrent, 0, len(f.torrents))
+	for _, torrent := range f.torrents {
+		if arg.Category != nil && torrent.Category != *arg.Category {
+			continue
+		}
+		if arg.Tag != nil && *arg.Tag == "" && len(torrent.Tags) > 0 {
+			continue
+		}
+		if arg.Tag != nil && *arg.Tag != "" && !slices.Contains(torrent.Tags, *arg.Tag) {
+			continue
+		}
+		out = append(out, torrent)
+	}
+	return out, nil
+}
+
+func (f *fakeTorrentClient) AddTorrent(_ context.Context, arg *torrentclient.AddTorrentConfig) error {
+	f.added = append(f.added, arg)
+	return nil
+}
+
+func (f *fakeTorrentClient) update(hashes []string, fn func(torrent *torrentclient.Torrent)) {
+	for i := range f.torrents {
+		if slices.Contains(hashes, f.torrents[i].Hash) {
+			fn(&f.torrents[i])
+		}
+	}
+}
+
+func (f *fakeTorrentClient) AddTorrentTags(_ context.Context, hashes []string, tags []string) error {
+	f.update(hashes, func(torrent *torrentclient.Torrent) {
+		for _, tag := range tags {
+			if !slices.Contains(torrent.Tags, tag) {
+				torrent.Tags = append(torrent.Tags, tag)
+			}
+		}
+		slices.Sort(torrent.Tags)
+	})
+	return nil
+}
+
+func (f *fakeTorrentClient) RemoveTorrentTags(_ context.Context, hashes []string, tags []string) error {
+	f.update(hashes, func(torrent *torrentclient.Torrent) {
+		torrent.Tags = slices.DeleteFunc(torrent.Tags, func(tag string) bool {
+			return slices.Contains(tags, tag)
+		})
+	})
+	return nil
+}
+
+func (f *fakeTorrentClient) DeleteTorrents(_ context.Context, hashes []string, deleteFiles bool) error {
+	f.deleted = append(f.deleted, hashes...)
+	f.deleteFiles = deleteFiles
+	f.torrents = slices.DeleteFunc(f.torrents, func(torrent torrentclient.Torrent) bool {
+		return slices.Contains(hashes, torrent.Hash)
+	})
+	return nil
+}
+
+func (f *fakeTorrentClient) SetShareLimits(_ context.Context, hashes []string, limits torrentclient.ShareLimits) error {
+	if f.limits == nil {
+		f.limits = make(map[string]torrentclient.ShareLimits)
+	}
+	for _, hash := range hashes {
+		f.limits[hash] = limits
+	}
+	f.update(hashes, func(torrent *torrentclient.Torrent) {
+		torrent.ShareLimits = limits
+	})
+	return nil
+}
+
+func (f *fakeTorrentClient) ListFiles(_ context.Context, hash string) ([]torrentclient.File, error) {
+	return f.files[hash], nil
+}
+
+func (f *fakeTorrentClient) SetFilePriority(_ context.Context, hash string, indexes []int, priority torrentclient.FilePriority) error {
+	for i := range f.files[hash] {
+		if slices.Contains(indexes, f.files[hash][i].Index) {
+			f.files[hash][i].Priority = priority
+		}
+	}
+	return nil
+}
+
+func (f *fakeTorrentClient) SetLocation(_ context.Context, hashes []string, location string) error {
+	f.update(hashes, func(torrent *torrentclient.Torrent) {
+		torrent.SavePath = location
+	})
+	return nil
+}
+
+func (f *fakeTorrentClient) SetTransferLimits(_ context.Context, hashes []string, limits torrentclient.TransferLimits) error {
+	f.update(hashes, func(torrent *torrentclient.Torrent) {
+		torrent.Limits = limits
+	})
+	return nil
+}
+
+func (f *fakeTorrentClient) EnsureCategory(_ context.Context, name, savePath string) error {
+	if f.categories == nil {
+		f.categories = make(map[string]string)
+	}
+	f.categories[name] = savePath
+	return nil
+}
+
+// fakeShowStore is an in-memory ShowStore.
+// failingTorrentClient fails listing torrents with err.
+type failingTorrentClient struct {
+	*fakeTorrentClient
+	err error
+}
+
+func (f failingTorrentClient) List(context.Context, *torrentclient.ListTorrentConfig) ([]torrentclient.Torrent, error) {
+	return nil, f.err
+}
+
+type fakeShowStore map[string]string
+
+func (f fakeShowStore) GetTitleTag(key string) (string, bool) {
+	tag, ok := f[key]
+	return tag, ok
+}
+
+func (f fakeShowStore) SetTitleTag(key, tag string) error {
+	f[key] = tag
+	return nil
+}
+
+// fakeAnimeList is an in-memory AnimeListSource, filtering entries by list status.
+type fakeAnimeList []animelist.Entry
+
+func (f fakeAnimeList) GetAnimeList(_ context.Context, statuses ...animelist.ListStatus) ([]animelist.Entry, error) {
+	var out []animelist.Entry
+	for _, entry := range f {
+		if slices.Contains(statuses, entry.ListStatus) {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+// fakeBlocklist is an in-memory Blocklist.
+// fakeAnimeListWriter records the progress of each updated entry, by title.
+type fakeAnimeListWriter map[string]int
+
+func (f fakeAnimeListWriter) UpdateProgress(_ context.Context, entry animelist.Entry, progress int, _ animelist.ListStatus) error {
+	f[entry.Titles[0]] = progress
+	return nil
+}
+
+type fakeBlocklist map[string]string
+
+func (f fakeBlocklist) IsBlocked(hash string) bool {
+	_, ok := f[hash]
+	return ok
+}
+
+func (f fakeBlocklist) Block(hash, reason string) error {
+	f[hash] = reason
+	return nil
+}
+
+// failingBlocklist is a Blocklist failing to persist blocked releases.
+type failingBlocklist struct{}
+
+func (failingBlocklist) IsBlocked(string) bool {
+	return false
+}
+
+func (failingBlocklist) Block(string, string) error {
+	return errors.New("blocklist is read only")
+}
+
+// fakeProcessedStore is an in-memory ProcessedStore.
+type fakeProcessedStore struct {
+	hashes map[string]struct{}
+	files  map[string]int
+}
+
+func (f *fakeProcessedStore) IsProcessed(hash string) bool {
+	_, ok := f.hashes[hash]
+	return ok
+}
+
+func (f *fakeProcessedStore) MarkProcessed(hash string) error {
+	if f.hashes == nil {
+		f.hashes = make(map[string]struct{})
+	}
+	f.hashes[hash] = struct{}{}
+	return nil
+}
+
+func (f *fakeProcessedStore) ImportedVersion(destination string) (int, bool) {
+	version, ok := f.files[destination]
+	return version, ok
+}
+
+func (f *fakeProcessedStore) MarkImported(destination string, version int) error {
+	if f.files == nil {
+		f.files = make(map[string]int)
+	}
+	f.files[destination] = version
+	return nil
+}
+
+// fakeMediaServer records the refreshed paths.
+type fakeMediaServer struct {
+	refreshed []string
+}
+
+func (f *fakeMediaServer) RefreshPath(_ context.Context, path string) error {
+	f.refreshed = append(f.refreshed, path)
+	return nil
+}
+
+// fakeOwnedStore is an in-memory OwnedStore.
+type fakeOwnedStore map[string][]tags.Tag
+
+func (f fakeOwnedStore) OwnedEpisodes(key string) []tags.Tag {
+	return f[key]
+}
+
+func (f fakeOwnedStore) AddOwnedEpisodes(key string, episodes ...tags.Tag) error {
+	f[key] = append(f[key], episodes...)
+	return nil
+}
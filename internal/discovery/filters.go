@@ -1,9 +1,11 @@
 package discovery
 
 import (
+	"slices"
 	"time"
 
 	"github.com/sonalys/animeman/internal/integrations/nyaa"
+	"github.com/sonalys/animeman/internal/matcher"
 	"github.com/sonalys/animeman/internal/parser"
 	"github.com/sonalys/animeman/internal/utils"
 	"github.com/sonalys/animeman/pkg/v1/animelist"
@@ -11,12 +13,94 @@ import (
 
 const ignoreCharset = " \t!,.:`'\"/\\;-[](){}*【】"
 
+// filterListStatus ensures only anime list entries with the configured statuses are considered for discovery.
+// Plan to watch entries are only considered when they start airing within the configured window,
+// or when batch only is configured, once they have finished airing. Both can't be configured together,
+// since aired entries already started airing before the window.
+func filterListStatus(config Config, now time.Time) func(entry animelist.Entry) bool {
+	return func(entry animelist.Entry) bool {
+		if len(config.ListStatuses) > 0 &&
+			!slices.Contains(config.ListStatuses, animelist.ListStatusAll) &&
+			!slices.Contains(config.ListStatuses, entry.ListStatus) {
+			return false
+		}
+
+		if entry.ListStatus != animelist.ListStatusPlanToWatch {
+			return true
+		}
+
+		if config.PlanToWatchBatchOnly && entry.AiringStatus != animelist.AiringStatusAired {
+			return false
+		}
+
+		if config.PlanToWatchAiringWithin > 0 && entry.StartDate.After(now.Add(config.PlanToWatchAiringWithin)) {
+			return false
+		}
+
+		return true
+	}
+}
+
+// isBatchOnly returns true when only batch releases should be downloaded for the given entry.
+func (c Config) isBatchOnly(entry animelist.Entry) bool {
+	return c.PlanToWatchBatchOnly && entry.ListStatus == animelist.ListStatusPlanToWatch
+}
+
+// filterProgress ensures only episodes relevant to the user's list progress are downloaded.
+// With EpisodesAhead, releases after progress + EpisodesAhead are discarded.
+// With SkipWatched, releases containing only watched episodes are discarded.
+func filterProgress(
+	config Config,
+	entry animelist.Entry,
+	filterData *FilterData,
+) func(e parser.ParsedNyaa) bool {
+	return func(nyaaEntry parser.ParsedNyaa) bool {
+		tag := nyaaEntry.ExtractedMetadata.Tag
+
+		lastEpisode := tag.LastEpisode()
+		// Season batches don't specify episodes, so they contain the whole season.
+		if lastEpisode == 0 {
+			lastEpisode = float64(entry.NumEpisodes)
+		}
+
+		if config.EpisodesAhead > 0 {
+			limit := float64(entry.Progress + config.EpisodesAhead)
+			if lastEpisode == 0 || lastEpisode > limit {
+				filterData.DiscardReason[DiscardReasonAheadOfProgress]++
+				return false
+			}
+		}
+
+		if config.SkipWatched && lastEpisode > 0 && lastEpisode <= float64(entry.Progress) {
+			filterData.DiscardReason[DiscardReasonWatched]++
+			return false
+		}
+
+		return true
+	}
+}
+
+// titleMatchThreshold returns the minimum score for a release to match one of the entry titles.
+func (c Config) titleMatchThreshold() float64 {
+	if c.TitleMatchThreshold > 0 {
+		return c.TitleMatchThreshold
+	}
+
+	return matcher.DefaultThreshold
+}
+
 // filterMetadata ensures that only coherent and expected nyaa entries are considered for donwload.
 // This function avoids download unrelated torrents.
+// titles are the titles the nyaa entry is matched against, see entryTitles.
 func filterMetadata(
+	config Config,
 	entry animelist.Entry,
+	titles []string,
 	filterData *FilterData,
 ) func(e nyaa.Item) bool {
+	mapping := episodeMapping(entry)
+	threshold := config.titleMatchThreshold()
+
 	return func(nyaaEntry nyaa.Item) bool {
 		publishedDate := utils.Must(time.Parse(time.RFC1123Z, nyaaEntry.PubDate))
 
@@ -27,28 +111,28 @@ func filterMetadata(
 			return false
 		}
 
-		meta := parser.Parse(nyaaEntry.Title, 1)
+		meta := parser.Parse(nyaaEntry.Title, parser.EntrySeason(entry))
+		tag := mapping.ToSeasonal(meta.Tag)
 
 		// Check if nyaa entry episode is greater than the animelist episode count.
-		if entry.NumEpisodes > 0 && meta.Tag.LastEpisode() > float64(entry.NumEpisodes) {
+		// Movies and single releases are skipped, since numbers in their titles are often parsed as episodes.
+		if !isSingleRelease(entry) &&
+			entry.NumEpisodes > 0 && tag.LastEpisode() > float64(entry.NumEpisodes) {
 			filterData.DiscardReason[DiscardReasonEpisodeCountMismatch]++
 
 			return false
 		}
 
-		nyaaTitleWithoutTags := parser.StripTags(nyaaEntry.Title)
-
-		for _, originalTitle := range entry.Titles {
-			// Remove season information from the original title, as it is not always present in the nyaa entry.
-			originalTitleWithoutSeason := parser.StripSeason(originalTitle)
-			originalTitleWithoutSubtitle := parser.StripSubtitle(originalTitleWithoutSeason)
-
-			if utils.MatchPrefixFlexible(nyaaTitleWithoutTags, originalTitleWithoutSubtitle, ignoreCharset) {
-				return true
-			}
+		match := matcher.Best(nyaaEntry.Title, titles)
+		if match.Score >= threshold {
+			return true
 		}
 
-		filterData.DiscardReason[DiscardReasonTitleMismatch]++
+		filterData.addTitleMismatch(TitleMismatch{
+			Release: nyaaEntry.Title,
+			Title:   match.Title,
+			Score:   match.Score,
+		})
 
 		return false
 	}
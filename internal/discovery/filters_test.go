@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/sonalys/animeman/internal/integrations/nyaa"
+	"github.com/sonalys/animeman/internal/parser"
+	"github.com/sonalys/animeman/internal/tags"
 	"github.com/sonalys/animeman/internal/utils"
 	"github.com/sonalys/animeman/pkg/v1/animelist"
 	"github.com/stretchr/testify/assert"
@@ -111,6 +113,29 @@ func Test_filterMetadata(t *testing.T) {
 			},
 			wantResult: true,
 		},
+		{
+			name: "Valid - Synonym match",
+			entry: animelist.Entry{
+				Titles:   []string{"Shingeki no Kyojin"},
+				Synonyms: []string{"Attack on Titan"},
+			},
+			nyaaItem: nyaa.Item{
+				Title:   "[Subs] Attack on Titan - 01",
+				PubDate: fmtTime(now),
+			},
+			wantResult: true,
+		},
+		{
+			name: "Valid - Romanization differences",
+			entry: animelist.Entry{
+				Titles: []string{"Kyōkai no Kanata"},
+			},
+			nyaaItem: nyaa.Item{
+				Title:   "[Subs] Kyoukai no Kanata - 01",
+				PubDate: fmtTime(now),
+			},
+			wantResult: true,
+		},
 		{
 			name: "Invalid - Completely different title",
 			entry: animelist.Entry{
@@ -148,9 +173,199 @@ func Test_filterMetadata(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filter := filterMetadata(tt.entry, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+			filter := filterMetadata(Config{}, tt.entry, entryTitles(tt.entry, nil), &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 			got := filter(tt.nyaaItem)
 			assert.Equal(t, tt.wantResult, got)
 		})
 	}
 }
+
+func Test_filterMetadata_titleMismatch(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	entry := animelist.Entry{Titles: []string{"Kusuriya no Hitorigoto"}}
+	item := nyaa.Item{
+		Title:   "[Subs] Kusuriya no Hitorigito - 01",
+		PubDate: now.Format(time.RFC1123Z),
+	}
+
+	filterData := &FilterData{DiscardReason: make(map[DiscardReason]uint)}
+	require.True(t, filterMetadata(Config{}, entry, entry.Titles, filterData)(item))
+
+	filter := filterMetadata(Config{TitleMatchThreshold: 1}, entry, entry.Titles, filterData)
+	require.False(t, filter(item))
+	require.Equal(t, uint(1), filterData.DiscardReason[DiscardReasonTitleMismatch])
+	require.Len(t, filterData.TitleMismatches, 1)
+	require.Equal(t, item.Title, filterData.TitleMismatches[0].Release)
+	require.Equal(t, "Kusuriya no Hitorigoto", filterData.TitleMismatches[0].Title)
+	require.InDelta(t, 0.95, filterData.TitleMismatches[0].Score, 0.01)
+}
+
+func Test_FilterData_addTitleMismatch(t *testing.T) {
+	filterData := &FilterData{DiscardReason: make(map[DiscardReason]uint)}
+	for i := range maxTitleMismatches + 2 {
+		filterData.addTitleMismatch(TitleMismatch{Score: float64(i) / 10})
+	}
+
+	require.Equal(t, uint(maxTitleMismatches+2), filterData.DiscardReason[DiscardReasonTitleMismatch])
+	require.Len(t, filterData.TitleMismatches, maxTitleMismatches)
+	require.Equal(t, 0.6, filterData.TitleMismatches[0].Score)
+	require.Equal(t, 0.2, filterData.TitleMismatches[maxTitleMismatches-1].Score)
+}
+
+func Test_filterListStatus(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		config Config
+		entry  animelist.Entry
+		want   bool
+	}{
+		{
+			name:   "no configured statuses",
+			config: Config{},
+			entry:  animelist.Entry{ListStatus: animelist.ListStatusOnHold},
+			want:   true,
+		},
+		{
+			name:   "status not configured",
+			config: Config{ListStatuses: []animelist.ListStatus{animelist.ListStatusWatching}},
+			entry:  animelist.Entry{ListStatus: animelist.ListStatusOnHold},
+			want:   false,
+		},
+		{
+			name:   "all statuses",
+			config: Config{ListStatuses: []animelist.ListStatus{animelist.ListStatusAll}},
+			entry:  animelist.Entry{ListStatus: animelist.ListStatusDropped},
+			want:   true,
+		},
+		{
+			name: "plan to watch airing within window",
+			config: Config{
+				ListStatuses:            []animelist.ListStatus{animelist.ListStatusPlanToWatch},
+				PlanToWatchAiringWithin: 7 * 24 * time.Hour,
+			},
+			entry: animelist.Entry{
+				ListStatus:   animelist.ListStatusPlanToWatch,
+				AiringStatus: animelist.AiringStatusNotYetAired,
+				StartDate:    now.AddDate(0, 0, 3),
+			},
+			want: true,
+		},
+		{
+			name: "plan to watch airing outside window",
+			config: Config{
+				ListStatuses:            []animelist.ListStatus{animelist.ListStatusPlanToWatch},
+				PlanToWatchAiringWithin: 7 * 24 * time.Hour,
+			},
+			entry: animelist.Entry{
+				ListStatus:   animelist.ListStatusPlanToWatch,
+				AiringStatus: animelist.AiringStatusNotYetAired,
+				StartDate:    now.AddDate(0, 1, 0),
+			},
+			want: false,
+		},
+		{
+			name: "plan to watch batch only still airing",
+			config: Config{
+				ListStatuses:         []animelist.ListStatus{animelist.ListStatusPlanToWatch},
+				PlanToWatchBatchOnly: true,
+			},
+			entry: animelist.Entry{
+				ListStatus:   animelist.ListStatusPlanToWatch,
+				AiringStatus: animelist.AiringStatusAiring,
+			},
+			want: false,
+		},
+		{
+			name: "plan to watch batch only aired",
+			config: Config{
+				ListStatuses:         []animelist.ListStatus{animelist.ListStatusPlanToWatch},
+				PlanToWatchBatchOnly: true,
+			},
+			entry: animelist.Entry{
+				ListStatus:   animelist.ListStatusPlanToWatch,
+				AiringStatus: animelist.AiringStatusAired,
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterListStatus(tt.config, now)(tt.entry)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_filterProgress(t *testing.T) {
+	episode := func(ep float64) parser.ParsedNyaa {
+		return parser.ParsedNyaa{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, ep)}}
+	}
+	batch := parser.ParsedNyaa{ExtractedMetadata: parser.Metadata{Tag: tags.Tag{Seasons: []int{1}}}}
+
+	tests := []struct {
+		name   string
+		config Config
+		entry  animelist.Entry
+		item   parser.ParsedNyaa
+		want   bool
+	}{
+		{
+			name:  "disabled",
+			entry: animelist.Entry{Progress: 3},
+			item:  episode(10),
+			want:  true,
+		},
+		{
+			name:   "within episodes ahead",
+			config: Config{EpisodesAhead: 2},
+			entry:  animelist.Entry{Progress: 3},
+			item:   episode(5),
+			want:   true,
+		},
+		{
+			name:   "beyond episodes ahead",
+			config: Config{EpisodesAhead: 2},
+			entry:  animelist.Entry{Progress: 3},
+			item:   episode(6),
+			want:   false,
+		},
+		{
+			name:   "season batch beyond episodes ahead",
+			config: Config{EpisodesAhead: 2},
+			entry:  animelist.Entry{Progress: 3, NumEpisodes: 12},
+			item:   batch,
+			want:   false,
+		},
+		{
+			name:   "season batch with unknown episode count",
+			config: Config{EpisodesAhead: 2},
+			entry:  animelist.Entry{Progress: 3},
+			item:   batch,
+			want:   false,
+		},
+		{
+			name:   "skip watched",
+			config: Config{SkipWatched: true},
+			entry:  animelist.Entry{Progress: 3},
+			item:   episode(3),
+			want:   false,
+		},
+		{
+			name:   "skip watched unwatched episode",
+			config: Config{SkipWatched: true},
+			entry:  animelist.Entry{Progress: 3},
+			item:   episode(4),
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := filterProgress(tt.config, tt.entry, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+			assert.Equal(t, tt.want, filter(tt.item))
+		})
+	}
+}
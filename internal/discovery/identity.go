@@ -0,0 +1,147 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/animedb"
+	"github.com/sonalys/animeman/internal/parser"
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// maxSearchSynonyms limits the synonyms used for searching, avoiding huge queries.
+const maxSearchSynonyms = 5
+
+// enrichEntry fills the entry identifiers and synonyms from the anime database.
+func enrichEntry(db *animedb.DB) func(entry animelist.Entry) animelist.Entry {
+	return func(entry animelist.Entry) animelist.Entry {
+		record, ok := db.Resolve(entry.IDs)
+		if !ok {
+			return entry
+		}
+
+		entry.IDs = entry.IDs.Merge(record.IDs)
+		entry.Synonyms = append(slices.Clone(entry.Synonyms), record.Synonyms...)
+
+		return entry
+	}
+}
+
+// entryAliases returns the configured aliases for an entry, matching it's titles or identity.
+func entryAliases(entry animelist.Entry, aliases map[string][]string) []string {
+	var out []string
+
+	for key, terms := range aliases {
+		if entry.IDs.HasKey(key) || slices.ContainsFunc(entry.Titles, func(title string) bool {
+			return strings.EqualFold(title, key)
+		}) {
+			out = append(out, terms...)
+		}
+	}
+
+	slices.Sort(out)
+
+	return slices.Compact(out)
+}
+
+// entryTitles returns the entry titles, followed by it's configured aliases and ASCII synonyms.
+// Synonyms in other scripts are ignored, since releases are mostly named with romanized or english titles.
+func entryTitles(entry animelist.Entry, aliases map[string][]string) []string {
+	titles := slices.Clone(entry.Titles)
+
+	containsTitle := func(term string) bool {
+		return slices.ContainsFunc(titles, func(title string) bool {
+			return strings.EqualFold(title, term)
+		})
+	}
+
+	for _, alias := range entryAliases(entry, aliases) {
+		if alias != "" && !containsTitle(alias) {
+			titles = append(titles, alias)
+		}
+	}
+
+	synonyms := 0
+	for _, synonym := range entry.Synonyms {
+		if synonyms >= maxSearchSynonyms {
+			break
+		}
+
+		if synonym == "" || !isASCII(synonym) || containsTitle(synonym) {
+			continue
+		}
+
+		titles = append(titles, synonym)
+		synonyms++
+	}
+
+	return titles
+}
+
+// entryTitleTag returns the torrent title tag used for new torrents of the entry.
+func entryTitleTag(entry animelist.Entry) string {
+	return parser.BuildTitleTag(selectIdealTitle(entry.Titles))
+}
+
+// storedTitleTag returns the title tag stored for the entry, by any of it's identifiers.
+// Identifiers are tried from the most preferred, since the entry key changes when new identifiers are known.
+func (c *Controller) storedTitleTag(entry animelist.Entry) (string, bool) {
+	for _, key := range entry.IDs.Keys() {
+		if tag, ok := c.dep.ShowStore.GetTitleTag(key); ok {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+// migrateTitleTag keeps the episode history of renamed shows.
+// When the title tag of a show changes, torrents with the previous title tag are re-tagged with the new one.
+func (c *Controller) migrateTitleTag(ctx context.Context, entry animelist.Entry) error {
+	key := entry.IDs.Key()
+	if c.dep.ShowStore == nil || key == "" {
+		return nil
+	}
+
+	logger := getLogger(ctx)
+	titleTag := entryTitleTag(entry)
+
+	previousTag, ok := c.storedTitleTag(entry)
+	if ok && previousTag != titleTag {
+		torrents, err := c.dep.TorrentClient.List(ctx, &torrentclient.ListTorrentConfig{
+			Category: &c.dep.Config.Category,
+			Tag:      utils.Pointer(previousTag),
+		})
+		if err != nil {
+			return fmt.Errorf("listing torrents: %w", err)
+		}
+
+		if len(torrents) > 0 {
+			hashes := utils.Map(torrents, func(t torrentclient.Torrent) string { return t.Hash })
+
+			if err := c.dep.TorrentClient.AddTorrentTags(ctx, hashes, []string{titleTag}); err != nil {
+				return fmt.Errorf("adding title tag: %w", err)
+			}
+
+			if err := c.dep.TorrentClient.RemoveTorrentTags(ctx, hashes, []string{previousTag}); err != nil {
+				return fmt.Errorf("removing previous title tag: %w", err)
+			}
+		}
+
+		logger.
+			Info().
+			Str("previousTag", previousTag).
+			Str("titleTag", titleTag).
+			Int("torrents", len(torrents)).
+			Msg("migrated renamed show title tag")
+	}
+
+	if err := c.dep.ShowStore.SetTitleTag(key, titleTag); err != nil {
+		return fmt.Errorf("storing title tag: %w", err)
+	}
+
+	return nil
+}
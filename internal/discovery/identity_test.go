@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/animedb"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_enrichEntry(t *testing.T) {
+	db := animedb.New(animedb.Record{
+		IDs:      animelist.IDs{AniList: 1, MAL: 1, AniDB: 4563},
+		Title:    "Cowboy Bebop",
+		Synonyms: []string{"Bebop"},
+	})
+
+	t.Run("found", func(t *testing.T) {
+		got := enrichEntry(db)(animelist.Entry{IDs: animelist.IDs{MAL: 1}})
+		require.Equal(t, animelist.IDs{AniList: 1, MAL: 1, AniDB: 4563}, got.IDs)
+		require.Equal(t, []string{"Bebop"}, got.Synonyms)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		entry := animelist.Entry{IDs: animelist.IDs{MAL: 2}}
+		require.Equal(t, entry, enrichEntry(db)(entry))
+	})
+
+	t.Run("no database", func(t *testing.T) {
+		entry := animelist.Entry{IDs: animelist.IDs{MAL: 1}}
+		require.Equal(t, entry, enrichEntry(nil)(entry))
+	})
+}
+
+func Test_entryTitles(t *testing.T) {
+	entry := animelist.Entry{
+		Titles:   []string{"Cowboy Bebop", "カウボーイビバップ"},
+		Synonyms: []string{"cowboy bebop", "カウボーイビバップ TV", "Bebop", "", "A", "B", "C", "D", "E"},
+	}
+
+	got := entryTitles(entry, nil)
+	require.Equal(t, []string{"Cowboy Bebop", "カウボーイビバップ", "Bebop", "A", "B", "C", "D"}, got)
+}
+
+func Test_entryTitles_aliases(t *testing.T) {
+	entry := animelist.Entry{
+		IDs:    animelist.IDs{AniList: 16498},
+		Titles: []string{"Shingeki no Kyojin"},
+	}
+
+	aliases := map[string][]string{
+		"shingeki no kyojin": {"SnK", "Attack on Titan"},
+		"anilist:16498":      {"AoT", "SnK"},
+		"Another Show":       {"Another"},
+	}
+
+	got := entryTitles(entry, aliases)
+	require.Equal(t, []string{"Shingeki no Kyojin", "AoT", "Attack on Titan", "SnK"}, got)
+}
+
+func Test_migrateTitleTag(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	entry := animelist.Entry{
+		IDs:    animelist.IDs{AniList: 1},
+		Titles: []string{"New Title"},
+	}
+
+	t.Run("renamed show", func(t *testing.T) {
+		torrentClient := &fakeTorrentClient{
+			torrents: []torrentclient.Torrent{
+				{Hash: "1", Tags: []string{"!old title", "S1E1"}},
+				{Hash: "2", Tags: []string{"!another show", "S1E1"}},
+			},
+		}
+		showStore := fakeShowStore{"anilist:1": "!old title"}
+
+		c := New(Dependencies{TorrentClient: torrentClient, ShowStore: showStore})
+		require.NoError(t, c.migrateTitleTag(ctx, entry))
+
+		require.Equal(t, []string{"!new title", "S1E1"}, torrentClient.torrents[0].Tags)
+		require.Equal(t, []string{"!another show", "S1E1"}, torrentClient.torrents[1].Tags)
+		require.Equal(t, "!new title", showStore["anilist:1"])
+	})
+
+	t.Run("stored by another identifier", func(t *testing.T) {
+		torrentClient := &fakeTorrentClient{
+			torrents: []torrentclient.Torrent{
+				{Hash: "1", Tags: []string{"!old title", "S1E1"}},
+			},
+		}
+		showStore := fakeShowStore{"mal:5": "!old title"}
+
+		c := New(Dependencies{TorrentClient: torrentClient, ShowStore: showStore})
+		require.NoError(t, c.migrateTitleTag(ctx, animelist.Entry{
+			IDs:    animelist.IDs{AniList: 1, MAL: 5},
+			Titles: []string{"New Title"},
+		}))
+
+		require.Equal(t, []string{"!new title", "S1E1"}, torrentClient.torrents[0].Tags)
+		require.Equal(t, "!new title", showStore["anilist:1"])
+	})
+
+	t.Run("new show", func(t *testing.T) {
+		showStore := fakeShowStore{}
+
+		c := New(Dependencies{TorrentClient: &fakeTorrentClient{}, ShowStore: showStore})
+		require.NoError(t, c.migrateTitleTag(ctx, entry))
+
+		require.Equal(t, "!new title", showStore["anilist:1"])
+	})
+
+	t.Run("without identity", func(t *testing.T) {
+		showStore := fakeShowStore{}
+
+		c := New(Dependencies{TorrentClient: &fakeTorrentClient{}, ShowStore: showStore})
+		require.NoError(t, c.migrateTitleTag(ctx, animelist.Entry{Titles: []string{"Show"}}))
+
+		require.Empty(t, showStore)
+	})
+}
@@ -0,0 +1,224 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/matcher"
+	"github.com/sonalys/animeman/internal/parser"
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+type (
+	// ImportedFile is a video file on disk, matched to an anime list entry.
+	ImportedFile struct {
+		// Path is relative to the imported directory.
+		Path  string
+		Title string
+		// Show is the key of the entry owned episodes, see ownedKey.
+		Show string
+		// Tag is the season episode of the file. Zero for movies.
+		Tag   tags.Tag
+		Score float64
+	}
+
+	// ImportReport lists the video files matched to anime list entries, and the ones without a match.
+	ImportReport struct {
+		Matched   []ImportedFile
+		Unmatched []string
+	}
+)
+
+// ownedKey returns the key of the entry owned episodes, it's identity or title tag when unknown.
+func ownedKey(entry animelist.Entry) string {
+	if key := entry.IDs.Key(); key != "" {
+		return key
+	}
+	return entryTitleTag(entry)
+}
+
+// ownedEpisodes returns the episodes of the entry imported from disk.
+// Episodes are looked up by every identifier of the entry, since it's key changes when new identifiers are known.
+func (c *Controller) ownedEpisodes(entry animelist.Entry) []tags.Tag {
+	if c.dep.OwnedStore == nil {
+		return nil
+	}
+
+	keys := entry.IDs.Keys()
+	if len(keys) == 0 {
+		keys = []string{entryTitleTag(entry)}
+	}
+
+	var owned []tags.Tag
+	for _, key := range keys {
+		owned = append(owned, c.dep.OwnedStore.OwnedEpisodes(key)...)
+	}
+	return owned
+}
+
+// librarySeason detects the season of a library file from it's name, like Show S2 - 03.mkv,
+// or from the closest folder naming one, like Show/Season 02/Show - 03.mkv.
+func librarySeason(name string, dirs []string) (int, bool) {
+	if parser.HasSeason(name) {
+		return parser.Parse(name, 0).Tag.FirstSeason(), true
+	}
+
+	for _, dir := range dirs {
+		if season := parser.ParseSeason(dir); season > 0 {
+			return season, true
+		}
+	}
+
+	return 0, false
+}
+
+// matchLibraryFile matches a video file to the entry with the most similar title, using the file name or it's folders.
+// Ties prefer the entry with the same season as the file, detected from the file name or it's folders.
+// Files without episodes, like extras, only match movies and single releases.
+func matchLibraryFile(config Config, entries []animelist.Entry, path string) (ImportedFile, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	candidates := []string{name}
+	for dir := filepath.Dir(path); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		candidates = append(candidates, filepath.Base(dir))
+	}
+
+	fileSeason, hasSeason := librarySeason(name, candidates[1:])
+	threshold := config.titleMatchThreshold()
+
+	var (
+		best       ImportedFile
+		bestSeason bool
+		found      bool
+	)
+
+	for _, entry := range entries {
+		titles := entryTitles(entry, config.Aliases)
+
+		var score float64
+		for _, candidate := range candidates {
+			score = max(score, matcher.Best(candidate, titles).Score)
+		}
+
+		if score < threshold {
+			continue
+		}
+
+		season := parser.EntrySeason(entry)
+		fallback := season
+		if hasSeason {
+			fallback = fileSeason
+		}
+		meta := parser.Parse(name, fallback)
+		tag := episodeMapping(entry).ToSeasonal(meta.Tag)
+
+		switch {
+		case isSingleRelease(entry):
+			tag = tags.Tag{}
+		case len(tag.Episodes) == 0:
+			continue
+		case entry.NumEpisodes > 0 && tag.LastEpisode() > float64(entry.NumEpisodes):
+			continue
+		case entry.Format == animelist.MediaFormatSpecial || len(tag.Seasons) == 0:
+			tag.Seasons = []int{season}
+		}
+
+		sameSeason := hasSeason && fileSeason == season
+		if found && (score < best.Score || score == best.Score && (bestSeason || !sameSeason)) {
+			continue
+		}
+
+		best = ImportedFile{
+			Path:  path,
+			Title: selectIdealTitle(entry.Titles),
+			Show:  ownedKey(entry),
+			Tag:   tag,
+			Score: score,
+		}
+		bestSeason = sameSeason
+		found = true
+	}
+
+	return best, found
+}
+
+// ImportLibrary scans a directory tree for video files, matching them to the anime list entries.
+// Matched episodes are recorded as owned, so discovery doesn't download them again, unless it's a dry run.
+func (c *Controller) ImportLibrary(ctx context.Context, root string, dryRun bool) (ImportReport, error) {
+	var report ImportReport
+
+	if c.dep.OwnedStore == nil && !dryRun {
+		return report, errors.New("owned episodes store is not configured")
+	}
+
+	logger := getLogger(ctx)
+
+	entries, err := c.dep.AnimeListClient.GetAnimeList(ctx, c.dep.Config.ListStatuses...)
+	if err != nil {
+		return report, fmt.Errorf("fetching anime list: %w", err)
+	}
+
+	entries = utils.Map(entries, applyEpisodeMappings(c.dep.Config.EpisodeMappings))
+	entries = utils.Map(entries, enrichEntry(c.dep.AnimeDB))
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || parser.ParseContainer(d.Name()) == "" {
+			return nil
+		}
+
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if file, ok := matchLibraryFile(c.dep.Config, entries, relative); ok {
+			report.Matched = append(report.Matched, file)
+		} else {
+			report.Unmatched = append(report.Unmatched, relative)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("scanning %s: %w", root, err)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	var shows []string
+	owned := make(map[string][]tags.Tag)
+
+	for _, file := range report.Matched {
+		if _, ok := owned[file.Show]; !ok {
+			shows = append(shows, file.Show)
+		}
+		owned[file.Show] = append(owned[file.Show], file.Tag)
+	}
+
+	for _, show := range shows {
+		if err := c.dep.OwnedStore.AddOwnedEpisodes(show, owned[show]...); err != nil {
+			return report, fmt.Errorf("storing owned episodes: %w", err)
+		}
+	}
+
+	logger.
+		Info().
+		Int("matched", len(report.Matched)).
+		Int("unmatched", len(report.Unmatched)).
+		Int("shows", len(shows)).
+		Msgf("imported library from %s", root)
+
+	return report, nil
+}
@@ -0,0 +1,160 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchLibraryFile(t *testing.T) {
+	entries := []animelist.Entry{
+		{Titles: []string{"Show"}, IDs: animelist.IDs{AniList: 1}, NumEpisodes: 12},
+		{Titles: []string{"Show Season 2"}, IDs: animelist.IDs{AniList: 2}, Season: 2, NumEpisodes: 12},
+		{Titles: []string{"Movie: Subtitle"}, Format: animelist.MediaFormatMovie},
+		{Titles: []string{"Twin"}, IDs: animelist.IDs{AniList: 3}, NumEpisodes: 12},
+		{Titles: []string{"Twin"}, IDs: animelist.IDs{AniList: 4}, Season: 2, NumEpisodes: 12},
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		want  tags.Tag
+		show  string
+		match bool
+	}{
+		{
+			name:  "file name",
+			path:  "[Group] Show - 05 [1080p].mkv",
+			want:  tags.SeasonEpisode(1, 5),
+			show:  "anilist:1",
+			match: true,
+		},
+		{
+			name:  "season",
+			path:  "Show/Season 02/Show - S02E03.mkv",
+			want:  tags.SeasonEpisode(2, 3),
+			show:  "anilist:2",
+			match: true,
+		},
+		{
+			name:  "folder",
+			path:  "Show/S01E07.mkv",
+			want:  tags.SeasonEpisode(1, 7),
+			show:  "anilist:1",
+			match: true,
+		},
+		{
+			name:  "movie",
+			path:  "Movie - Subtitle/Movie - Subtitle.mkv",
+			show:  "!movie subtitle",
+			match: true,
+		},
+		{
+			name:  "same title season folder",
+			path:  "Twin/Season 02/Twin - 03.mkv",
+			want:  tags.SeasonEpisode(2, 3),
+			show:  "anilist:4",
+			match: true,
+		},
+		{
+			name:  "same title season name",
+			path:  "Twin S2 - 04.mkv",
+			want:  tags.SeasonEpisode(2, 4),
+			show:  "anilist:4",
+			match: true,
+		},
+		{
+			name:  "same title without season",
+			path:  "Twin - 05.mkv",
+			want:  tags.SeasonEpisode(1, 5),
+			show:  "anilist:3",
+			match: true,
+		},
+		{
+			name: "extras",
+			path: "Show/Show - NCOP.mkv",
+		},
+		{
+			name: "episode count",
+			path: "Show - 25.mkv",
+		},
+		{
+			name: "unknown",
+			path: "Another - 01.mkv",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchLibraryFile(Config{}, entries, tt.path)
+			require.Equal(t, tt.match, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tt.path, got.Path)
+			require.Equal(t, tt.show, got.Show)
+			require.Equal(t, tt.want.String(), got.Tag.String())
+		})
+	}
+}
+
+func Test_ImportLibrary(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"Show/Show - 01.mkv", "Show/Show - 02.mkv", "Show/Show - 02.ass", "Other - 01.mkv"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	owned := fakeOwnedStore{}
+	c := New(Dependencies{
+		AnimeListClient: fakeAnimeList{
+			{Titles: []string{"Show"}, IDs: animelist.IDs{AniList: 1}, ListStatus: animelist.ListStatusWatching},
+		},
+		TorrentClient: &fakeTorrentClient{},
+		OwnedStore:    owned,
+		Config:        Config{ListStatuses: []animelist.ListStatus{animelist.ListStatusWatching}},
+	})
+
+	report, err := c.ImportLibrary(t.Context(), dir, true)
+	require.NoError(t, err)
+	require.Len(t, report.Matched, 2)
+	require.Equal(t, []string{"Other - 01.mkv"}, report.Unmatched)
+	require.Empty(t, owned)
+
+	_, err = c.ImportLibrary(t.Context(), dir, false)
+	require.NoError(t, err)
+	require.Equal(t, fakeOwnedStore{"anilist:1": {tags.SeasonEpisode(1, 1), tags.SeasonEpisode(1, 2)}}, owned)
+
+	// Owned episodes are not downloaded again.
+	entry := animelist.Entry{Titles: []string{"Show"}, IDs: animelist.IDs{AniList: 1}}
+	latestTag, downloaded, err := c.findLatestTag(t.Context(), entry)
+	require.NoError(t, err)
+	require.Equal(t, tags.SeasonEpisode(1, 2), latestTag)
+	require.Equal(t, "S1E1-2", downloaded.episodeSet().String())
+
+	movie := animelist.Entry{Titles: []string{"Movie"}, Format: animelist.MediaFormatMovie}
+	owned[ownedKey(movie)] = []tags.Tag{{}}
+	results, err := c.filterMovieResults(t.Context(), movie, nil, &FilterData{DiscardReason: map[DiscardReason]uint{}})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func Test_ownedEpisodes(t *testing.T) {
+	owned := fakeOwnedStore{
+		"mal:5":     {tags.SeasonEpisode(1, 1)},
+		"anilist:1": {tags.SeasonEpisode(1, 2)},
+		"!show":     {tags.SeasonEpisode(1, 3)},
+	}
+	c := New(Dependencies{OwnedStore: owned})
+
+	// Imported while the entry only had it's MAL identifier.
+	got := c.ownedEpisodes(animelist.Entry{Titles: []string{"Show"}, IDs: animelist.IDs{AniList: 1, MAL: 5}})
+	require.Equal(t, []tags.Tag{tags.SeasonEpisode(1, 2), tags.SeasonEpisode(1, 1)}, got)
+
+	got = c.ownedEpisodes(animelist.Entry{Titles: []string{"Show"}})
+	require.Equal(t, []tags.Tag{tags.SeasonEpisode(1, 3)}, got)
+}
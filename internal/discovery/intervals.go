@@ -18,7 +18,7 @@ type ShowScanState struct {
 type IntervalTracker struct {
 	mu            sync.RWMutex
 	pollFrequency time.Duration
-	state         map[string]ShowScanState // Key is the concatenated show titles
+	state         map[string]ShowScanState // Key is the show identity, see getShowKey.
 }
 
 // NewIntervalTracker creates a new interval tracker with a configured poll frequency.
@@ -29,14 +29,19 @@ func NewIntervalTracker(pollFrequency time.Duration) *IntervalTracker {
 	}
 }
 
-// getShowKey creates a unique key for a show based on its titles.
-func getShowKey(titles []string) string {
-	if len(titles) == 0 {
+// getShowKey creates a unique key for a show based on its identifiers.
+// Titles are only used when the show has no known identifier, since they can be renamed.
+func getShowKey(entry animelist.Entry) string {
+	if key := entry.IDs.Key(); key != "" {
+		return key
+	}
+
+	if len(entry.Titles) == 0 {
 		return ""
 	}
 
 	// Use the first title as the key (titles are sorted in Entry creation)
-	return titles[0]
+	return entry.Titles[0]
 }
 
 // getState retrieves the current scan state for a show.
@@ -44,7 +49,7 @@ func (it *IntervalTracker) getState(entry animelist.Entry) ShowScanState {
 	it.mu.RLock()
 	defer it.mu.RUnlock()
 
-	key := getShowKey(entry.Titles)
+	key := getShowKey(entry)
 	state, exists := it.state[key]
 	if !exists {
 		return ShowScanState{}
@@ -54,7 +59,7 @@ func (it *IntervalTracker) getState(entry animelist.Entry) ShowScanState {
 
 // UpdateState updates the scan state for a show after a discovery scan.
 func (it *IntervalTracker) UpdateState(entry animelist.Entry, foundNewEpisodes bool) time.Time {
-	key := getShowKey(entry.Titles)
+	key := getShowKey(entry)
 	nextInterval := it.calculateNextInterval(entry)
 	nextScanTime := time.Now().Add(nextInterval)
 
@@ -68,6 +73,13 @@ func (it *IntervalTracker) UpdateState(entry animelist.Entry, foundNewEpisodes b
 	return nextScanTime
 }
 
+// Reset forgets the scan state of a show, so it's scanned right away.
+func (it *IntervalTracker) Reset(entry animelist.Entry) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	delete(it.state, getShowKey(entry))
+}
+
 // ShouldScanNow determines if a show should be scanned based on its last scan time and interval.
 // It should be scanned if next scan time is within the next poll frequency window, allowing for some flexibility in scheduling.
 func (it *IntervalTracker) ShouldScanNow(entry animelist.Entry) bool {
@@ -22,8 +22,21 @@ func TestGetShowKey(t *testing.T) {
 	tests := []struct {
 		name     string
 		titles   []string
+		ids      animelist.IDs
 		expected string
 	}{
+		{
+			name:     "anilist id",
+			titles:   []string{"Attack on Titan"},
+			ids:      animelist.IDs{AniList: 16498, MAL: 16498},
+			expected: "anilist:16498",
+		},
+		{
+			name:     "mal id",
+			titles:   []string{"Attack on Titan"},
+			ids:      animelist.IDs{MAL: 16498},
+			expected: "mal:16498",
+		},
 		{
 			name:     "single title",
 			titles:   []string{"Attack on Titan"},
@@ -48,7 +61,7 @@ func TestGetShowKey(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getShowKey(tt.titles)
+			result := getShowKey(animelist.Entry{Titles: tt.titles, IDs: tt.ids})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -201,7 +214,7 @@ func TestShouldScanNow(t *testing.T) {
 			name: "scan overdue",
 			setupFunc: func(it *IntervalTracker, entry animelist.Entry) {
 				tracker := it
-				key := getShowKey(entry.Titles)
+				key := getShowKey(entry)
 				tracker.mu.Lock()
 				tracker.state[key] = ShowScanState{
 					NextScanTime:     time.Now().Add(-1 * time.Minute),
@@ -245,7 +258,7 @@ func TestCalculateNextInterval(t *testing.T) {
 				Titles: []string{"New Episodes Found"},
 			},
 			setupState: func(it *IntervalTracker, entry animelist.Entry) {
-				key := getShowKey(entry.Titles)
+				key := getShowKey(entry)
 				it.mu.Lock()
 				it.state[key] = ShowScanState{
 					NextScanTime:     time.Now().Add(1 * time.Hour),
@@ -523,7 +536,7 @@ func TestShouldScanNow_WithinPollFrequency(t *testing.T) {
 	}
 
 	// Manually set next scan time to be far in the future
-	key := getShowKey(entry.Titles)
+	key := getShowKey(entry)
 	tracker.mu.Lock()
 	tracker.state[key] = ShowScanState{
 		NextScanTime:     time.Now().Add(10 * time.Minute),
@@ -566,7 +579,7 @@ func TestIntegration_FullScanCycle(t *testing.T) {
 	assert.True(t, state.FoundNewEpisodes)
 
 	// Manually advance the next scan time
-	key := getShowKey(entry.Titles)
+	key := getShowKey(entry)
 	tracker.mu.Lock()
 	tracker.state[key] = ShowScanState{
 		NextScanTime:     time.Now().Add(-1 * time.Minute),
@@ -0,0 +1,212 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// hasLifecycle returns true when seeding limits, transfer limits, or the removal of torrents, are configured.
+func (c Config) hasLifecycle() bool {
+	return c.SeedingLimits != torrentclient.ShareLimits{} ||
+		c.TransferLimits != torrentclient.TransferLimits{} ||
+		len(c.ShowSeedingLimits) > 0 ||
+		len(c.RemoveListStatuses) > 0
+}
+
+// showSeedingLimits returns the seeding limits configured for the entry, by title or identity key.
+func showSeedingLimits(entry animelist.Entry, limits map[string]torrentclient.ShareLimits) (torrentclient.ShareLimits, bool) {
+	for _, key := range entry.IDs.Keys() {
+		if limit, ok := limits[key]; ok {
+			return limit, true
+		}
+	}
+
+	for key, limit := range limits {
+		if slices.ContainsFunc(entry.Titles, func(title string) bool { return strings.EqualFold(title, key) }) {
+			return limit, true
+		}
+	}
+
+	return torrentclient.ShareLimits{}, false
+}
+
+// reachedSeedingLimits returns true when a downloaded torrent reached any of it's seeding limits.
+// Zero limits are never reached, since they are managed by the torrent client.
+func reachedSeedingLimits(torrent torrentclient.Torrent, limits torrentclient.ShareLimits) bool {
+	if torrent.Progress < 1 {
+		return false
+	}
+
+	return limits.Ratio > 0 && torrent.Ratio >= limits.Ratio ||
+		limits.SeedingTime > 0 && torrent.SeedingTime >= limits.SeedingTime
+}
+
+// ManageLifecycle applies the seeding and transfer limits to the torrents of the configured category.
+// Entries with their own seeding limits override the category limits of their torrents.
+// Torrents reaching their limits are removed, if configured, as well as the torrents of the removed entries,
+// the ones with a removal list status.
+func (c *Controller) ManageLifecycle(ctx context.Context, entries, removed []animelist.Entry) error {
+	torrents, err := c.dep.TorrentClient.List(ctx, &torrentclient.ListTorrentConfig{
+		Category: &c.dep.Config.Category,
+	})
+	if err != nil {
+		return fmt.Errorf("listing torrents: %w", err)
+	}
+
+	limits := make(map[string]torrentclient.ShareLimits, len(torrents))
+	for _, torrent := range torrents {
+		limits[torrent.Hash] = c.dep.Config.SeedingLimits
+	}
+
+	for _, entry := range entries {
+		showLimits, ok := showSeedingLimits(entry, c.dep.Config.ShowSeedingLimits)
+		if !ok {
+			continue
+		}
+
+		entryTorrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		for _, torrent := range entryTorrents {
+			limits[torrent.Hash] = showLimits
+		}
+	}
+
+	if err := c.setSeedingLimits(ctx, torrents, limits); err != nil {
+		return err
+	}
+
+	if err := c.setTransferLimits(ctx, torrents); err != nil {
+		return err
+	}
+
+	if c.dep.Config.RemoveOnSeedingLimit {
+		var hashes []string
+		for _, torrent := range torrents {
+			if reachedSeedingLimits(torrent, limits[torrent.Hash]) {
+				hashes = append(hashes, torrent.Hash)
+			}
+		}
+
+		if err := c.removeTorrents(ctx, hashes, "seeding limit reached"); err != nil {
+			return err
+		}
+	}
+
+	return c.removeListStatusTorrents(ctx, removed)
+}
+
+// setSeedingLimits sets the seeding limits of torrents, grouping torrents sharing the same limits.
+// Torrents without seeding limits, or with the same limits already, are left untouched.
+func (c *Controller) setSeedingLimits(ctx context.Context, torrents []torrentclient.Torrent, limits map[string]torrentclient.ShareLimits) error {
+	var groups []torrentclient.ShareLimits
+	hashes := make(map[torrentclient.ShareLimits][]string)
+
+	for _, torrent := range torrents {
+		limit := limits[torrent.Hash]
+		if limit == (torrentclient.ShareLimits{}) || torrent.ShareLimits == limit {
+			continue
+		}
+
+		if _, ok := hashes[limit]; !ok {
+			groups = append(groups, limit)
+		}
+		hashes[limit] = append(hashes[limit], torrent.Hash)
+	}
+
+	for _, limit := range groups {
+		if err := c.dep.TorrentClient.SetShareLimits(ctx, hashes[limit], limit); err != nil {
+			return fmt.Errorf("setting share limits: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// setTransferLimits sets the transfer limits of torrents with different limits, like torrents added before configuring them.
+func (c *Controller) setTransferLimits(ctx context.Context, torrents []torrentclient.Torrent) error {
+	limits := c.dep.Config.TransferLimits
+	if limits == (torrentclient.TransferLimits{}) {
+		return nil
+	}
+
+	var hashes []string
+	for _, torrent := range torrents {
+		if torrent.Limits != limits {
+			hashes = append(hashes, torrent.Hash)
+		}
+	}
+
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	if err := c.dep.TorrentClient.SetTransferLimits(ctx, hashes, limits); err != nil {
+		return fmt.Errorf("setting transfer limits: %w", err)
+	}
+
+	return nil
+}
+
+// isRemovalStatus returns true for entries with a list status removing their torrents, like completed or dropped.
+func (c Config) isRemovalStatus(entry animelist.Entry) bool {
+	return slices.Contains(c.RemoveListStatuses, entry.ListStatus)
+}
+
+// removeListStatusTorrents removes the torrents of shows with a removal list status, like completed or dropped.
+func (c *Controller) removeListStatusTorrents(ctx context.Context, entries []animelist.Entry) error {
+	for _, entry := range entries {
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		hashes := make([]string, 0, len(torrents))
+		for _, torrent := range torrents {
+			hashes = append(hashes, torrent.Hash)
+		}
+
+		if err := c.removeTorrents(ctx, hashes, fmt.Sprintf("show is %s on the anime list", entry.ListStatus)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeTorrents deletes torrents, keeping their files if configured.
+func (c *Controller) removeTorrents(ctx context.Context, hashes []string, reason string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	logger := getLogger(ctx)
+
+	if err := c.dep.TorrentClient.DeleteTorrents(ctx, hashes, !c.dep.Config.RemoveKeepFiles); err != nil {
+		return fmt.Errorf("deleting torrents: %w", err)
+	}
+
+	logger.
+		Info().
+		Strs("hashes", hashes).
+		Str("reason", reason).
+		Bool("keepFiles", c.dep.Config.RemoveKeepFiles).
+		Msg("removed torrents")
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reachedSeedingLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		torrent torrentclient.Torrent
+		limits  torrentclient.ShareLimits
+		want    bool
+	}{
+		{
+			name:    "no limits",
+			torrent: torrentclient.Torrent{Progress: 1, Ratio: 10},
+		},
+		{
+			name:    "ratio",
+			torrent: torrentclient.Torrent{Progress: 1, Ratio: 2},
+			limits:  torrentclient.ShareLimits{Ratio: 2},
+			want:    true,
+		},
+		{
+			name:    "below ratio",
+			torrent: torrentclient.Torrent{Progress: 1, Ratio: 1.5},
+			limits:  torrentclient.ShareLimits{Ratio: 2},
+		},
+		{
+			name:    "seeding time",
+			torrent: torrentclient.Torrent{Progress: 1, SeedingTime: 48 * time.Hour},
+			limits:  torrentclient.ShareLimits{Ratio: 2, SeedingTime: 24 * time.Hour},
+			want:    true,
+		},
+		{
+			name:    "downloading",
+			torrent: torrentclient.Torrent{Progress: 0.5, Ratio: 3},
+			limits:  torrentclient.ShareLimits{Ratio: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, reachedSeedingLimits(tt.torrent, tt.limits))
+		})
+	}
+}
+
+func Test_ManageLifecycle(t *testing.T) {
+	newClient := func() *fakeTorrentClient {
+		return &fakeTorrentClient{torrents: []torrentclient.Torrent{
+			{Hash: "a", Category: "anime", Tags: []string{"!show", "am:ep:S01E01"}, Progress: 1, Ratio: 1.5},
+			{Hash: "b", Category: "anime", Tags: []string{"!other", "am:ep:S01E01"}, Progress: 1, Ratio: 0.5},
+			{Hash: "c", Category: "anime", Tags: []string{"!dropped", "am:ep:S01E01"}, Progress: 0.2},
+			{Hash: "d", Category: "movies", Tags: []string{"!movie"}, Progress: 1, Ratio: 5},
+		}}
+	}
+	show := animelist.Entry{Titles: []string{"Show"}, ListStatus: animelist.ListStatusWatching}
+	dropped := animelist.Entry{Titles: []string{"Dropped"}, ListStatus: animelist.ListStatusDropped}
+
+	t.Run("seeding limits", func(t *testing.T) {
+		torrentClient := newClient()
+		c := New(Dependencies{TorrentClient: torrentClient, Config: Config{
+			Category:          "anime",
+			SeedingLimits:     torrentclient.ShareLimits{Ratio: 1},
+			ShowSeedingLimits: map[string]torrentclient.ShareLimits{"show": {Ratio: 2, SeedingTime: time.Hour}},
+		}})
+
+		require.NoError(t, c.ManageLifecycle(t.Context(), []animelist.Entry{show}, nil))
+		require.Equal(t, map[string]torrentclient.ShareLimits{
+			"a": {Ratio: 2, SeedingTime: time.Hour},
+			"b": {Ratio: 1},
+			"c": {Ratio: 1},
+		}, torrentClient.limits)
+		require.Empty(t, torrentClient.deleted)
+	})
+
+	t.Run("unchanged seeding limits", func(t *testing.T) {
+		torrentClient := newClient()
+		torrentClient.torrents[0].ShareLimits = torrentclient.ShareLimits{Ratio: 2, SeedingTime: time.Hour}
+		torrentClient.torrents[1].ShareLimits = torrentclient.ShareLimits{Ratio: 2}
+
+		c := New(Dependencies{TorrentClient: torrentClient, Config: Config{
+			Category:          "anime",
+			SeedingLimits:     torrentclient.ShareLimits{Ratio: 1},
+			ShowSeedingLimits: map[string]torrentclient.ShareLimits{"show": {Ratio: 2, SeedingTime: time.Hour}},
+		}})
+
+		require.NoError(t, c.ManageLifecycle(t.Context(), []animelist.Entry{show}, nil))
+		require.Equal(t, map[string]torrentclient.ShareLimits{
+			"b": {Ratio: 1},
+			"c": {Ratio: 1},
+		}, torrentClient.limits)
+
+		// The next cycle finds every torrent with it's limits.
+		torrentClient.limits = nil
+		require.NoError(t, c.ManageLifecycle(t.Context(), []animelist.Entry{show}, nil))
+		require.Empty(t, torrentClient.limits)
+	})
+
+	t.Run("transfer limits", func(t *testing.T) {
+		torrentClient := newClient()
+		limits := torrentclient.TransferLimits{Upload: 1 << 20}
+		torrentClient.torrents[1].Limits = limits
+
+		c := New(Dependencies{TorrentClient: torrentClient, Config: Config{
+			Category:       "anime",
+			TransferLimits: limits,
+		}})
+
+		require.NoError(t, c.ManageLifecycle(t.Context(), []animelist.Entry{show}, nil))
+		for _, torrent := range torrentClient.torrents {
+			if torrent.Category == "anime" {
+				require.Equal(t, limits, torrent.Limits)
+			} else {
+				require.Zero(t, torrent.Limits)
+			}
+		}
+		require.Empty(t, torrentClient.limits)
+	})
+
+	t.Run("remove on limit", func(t *testing.T) {
+		torrentClient := newClient()
+		c := New(Dependencies{TorrentClient: torrentClient, Config: Config{
+			Category:             "anime",
+			SeedingLimits:        torrentclient.ShareLimits{Ratio: 1},
+			RemoveOnSeedingLimit: true,
+		}})
+
+		require.NoError(t, c.ManageLifecycle(t.Context(), []animelist.Entry{show}, nil))
+		require.Equal(t, []string{"a"}, torrentClient.deleted)
+		require.True(t, torrentClient.deleteFiles)
+	})
+
+	t.Run("remove list statuses", func(t *testing.T) {
+		torrentClient := newClient()
+		c := New(Dependencies{
+			TorrentClient: torrentClient,
+			Config: Config{
+				Category:           "anime",
+				RemoveListStatuses: []animelist.ListStatus{animelist.ListStatusDropped},
+				RemoveKeepFiles:    true,
+			},
+		})
+
+		require.NoError(t, c.ManageLifecycle(t.Context(), []animelist.Entry{show}, []animelist.Entry{dropped}))
+		require.Equal(t, []string{"c"}, torrentClient.deleted)
+		require.False(t, torrentClient.deleteFiles)
+		require.Empty(t, torrentClient.limits)
+	})
+}
@@ -0,0 +1,56 @@
+package discovery
+
+import (
+	"strings"
+
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+// episodeMapping builds the absolute to season episode mapping for an entry.
+// When the entry season episode count is unknown, the airing schedule is used instead.
+func episodeMapping(entry animelist.Entry) tags.EpisodeMapping {
+	if len(entry.SeasonEpisodes) == 0 {
+		return tags.EpisodeMapping{}
+	}
+
+	seasons := append([]int{}, entry.SeasonEpisodes...)
+	last := len(seasons) - 1
+
+	if seasons[last] <= 0 {
+		seasons[last] = entry.NumEpisodes
+	}
+
+	if seasons[last] <= 0 {
+		for _, episode := range entry.EpisodeSchedule {
+			seasons[last] = max(seasons[last], episode.Number)
+		}
+	}
+
+	return tags.EpisodeMapping{Seasons: seasons}
+}
+
+// applyEpisodeMappings overrides the season episode counts of entries with the ones configured by title.
+// The configured counts start from the first season, and the last one is considered the entry season.
+func applyEpisodeMappings(mappings map[string][]int) func(entry animelist.Entry) animelist.Entry {
+	return func(entry animelist.Entry) animelist.Entry {
+		for title, seasons := range mappings {
+			if len(seasons) == 0 {
+				continue
+			}
+
+			for _, entryTitle := range entry.Titles {
+				if !strings.EqualFold(title, entryTitle) {
+					continue
+				}
+
+				entry.SeasonEpisodes = append([]int{}, seasons...)
+				entry.Season = len(seasons)
+
+				return entry
+			}
+		}
+
+		return entry
+	}
+}
@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/sonalys/animeman/internal/integrations/nyaa"
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_episodeMapping(t *testing.T) {
+	t.Run("no season episodes", func(t *testing.T) {
+		require.Empty(t, episodeMapping(animelist.Entry{}).Seasons)
+	})
+
+	t.Run("unknown count uses episode count", func(t *testing.T) {
+		entry := animelist.Entry{SeasonEpisodes: []int{12, 0}, NumEpisodes: 24}
+		require.Equal(t, []int{12, 24}, episodeMapping(entry).Seasons)
+	})
+
+	t.Run("unknown count uses airing schedule", func(t *testing.T) {
+		entry := animelist.Entry{
+			SeasonEpisodes: []int{12, 0},
+			EpisodeSchedule: []animelist.EpisodeSchedule{
+				{Number: 1},
+				{Number: 8},
+			},
+		}
+		require.Equal(t, []int{12, 8}, episodeMapping(entry).Seasons)
+	})
+}
+
+func Test_applyEpisodeMappings(t *testing.T) {
+	apply := applyEpisodeMappings(map[string][]int{
+		"show season 2": {12, 13},
+	})
+
+	got := apply(animelist.Entry{Titles: []string{"Show Season 2"}})
+	require.Equal(t, []int{12, 13}, got.SeasonEpisodes)
+	require.Equal(t, 2, got.Season)
+
+	got = apply(animelist.Entry{Titles: []string{"Another Show"}})
+	require.Empty(t, got.SeasonEpisodes)
+}
+
+func Test_absoluteEpisodes(t *testing.T) {
+	entry := animelist.Entry{
+		Titles:         []string{"Show"},
+		Season:         2,
+		SeasonEpisodes: []int{12, 13},
+	}
+
+	t.Run("parse results", func(t *testing.T) {
+		got := parseResults(entry, []nyaa.Item{
+			{Title: "[Group] Show - 15 [1080p]"},
+			{Title: "[Group] Show S02E04 [1080p]"},
+		})
+
+		require.Equal(t, tags.SeasonEpisode(2, 3), got[0].ExtractedMetadata.Tag)
+		require.Equal(t, tags.SeasonEpisode(2, 4), got[1].ExtractedMetadata.Tag)
+	})
+
+	t.Run("absolute numbering of the release group", func(t *testing.T) {
+		got := parseResults(entry, []nyaa.Item{
+			{Title: "[Group] Show - 13 [1080p]"},
+			{Title: "[Group] Show - 24 [1080p]"},
+			{Title: "[Other] Show - 13 [1080p]"},
+			{Title: "[Group] Show S02E04 [1080p]"},
+		})
+
+		require.Equal(t, tags.SeasonEpisode(2, 1), got[0].ExtractedMetadata.Tag)
+		require.Equal(t, tags.SeasonEpisode(2, 12), got[1].ExtractedMetadata.Tag)
+		require.Equal(t, tags.SeasonEpisode(2, 13), got[2].ExtractedMetadata.Tag)
+		require.Equal(t, tags.SeasonEpisode(2, 4), got[3].ExtractedMetadata.Tag)
+	})
+
+	t.Run("latest tag", func(t *testing.T) {
+		torrents := []torrentclient.Torrent{
+			{Tags: []string{"!show", "S2E2"}},
+			{Tags: []string{"!show", "S2E16"}},
+		}
+
+		got := getLatestTag(torrents, episodeMapping(entry))
+		require.Equal(t, tags.SeasonEpisode(2, 4), got)
+	})
+}
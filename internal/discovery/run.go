@@ -1,6 +1,7 @@
 package discovery
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -30,15 +31,39 @@ func (c *Controller) RunDiscovery(ctx context.Context) error {
 
 	ctx = log.Logger.WithContext(ctx)
 
+	// Torrents are listed once per cycle, changes made outside animeman are seen on the next one.
+	if c.torrents != nil {
+		c.torrents.reset()
+	}
+
+	if c.dep.Config.Category != "" && c.dep.Config.CategorySavePath != "" {
+		if err := c.dep.TorrentClient.EnsureCategory(ctx, c.dep.Config.Category, c.dep.Config.CategorySavePath); err != nil {
+			return fmt.Errorf("ensuring torrent category: %w", err)
+		}
+	}
+
 	if err := c.TorrentRegenerateTags(ctx); err != nil {
 		return fmt.Errorf("updating qBittorrent entries: %w", err)
 	}
 
-	entries, err := c.dep.AnimeListClient.GetCurrentlyWatching(ctx)
+	// The entries with a removal list status are fetched with the discovered ones, listing the anime list once per cycle.
+	entries, err := c.dep.AnimeListClient.GetAnimeList(ctx, slices.Concat(c.dep.Config.ListStatuses, c.dep.Config.RemoveListStatuses)...)
 	if err != nil {
 		return fmt.Errorf("fetching anime list: %w", err)
 	}
 
+	removed := utils.Filter(entries, c.dep.Config.isRemovalStatus)
+	removed = utils.Map(removed, enrichEntry(c.dep.AnimeDB))
+
+	entries = utils.Filter(entries, func(entry animelist.Entry) bool { return !c.dep.Config.isRemovalStatus(entry) })
+	entries = utils.Filter(entries, filterListStatus(c.dep.Config, time.Now()))
+	entries = utils.Map(entries, applyEpisodeMappings(c.dep.Config.EpisodeMappings))
+	entries = utils.Map(entries, enrichEntry(c.dep.AnimeDB))
+
+	if err := c.manageTorrents(ctx, entries, removed); err != nil {
+		return err
+	}
+
 	scannedCount := 0
 	skippedCount := 0
 
@@ -66,7 +91,7 @@ func (c *Controller) RunDiscovery(ctx context.Context) error {
 			Msgf("starting discovery for entry")
 
 		foundNew, err := c.DiscoverEntry(ctx, entry)
-		if errors.Is(err, torrentclient.ErrUnauthorized) || errors.Is(err, context.Canceled) {
+		if isFatalError(err) {
 			return fmt.Errorf("failed to digest entry: %w", err)
 		}
 
@@ -92,58 +117,177 @@ func (c *Controller) RunDiscovery(ctx context.Context) error {
 	return nil
 }
 
-// filterEpisodes will only return ParsedNyaa entries that are more recent than the given latestTag.
-// excludeBatch is used when a show is airing or you have already downloaded some episodes of the season.
-// excludeBatch avoids downloading a batch for episodes which you already have.
+// manageTorrents runs the passes managing the downloaded torrents and the anime list progress, before discovering new ones.
+// A failing pass is logged without stopping discovery, unless the torrent client can't be used at all.
+func (c *Controller) manageTorrents(ctx context.Context, entries, removed []animelist.Entry) error {
+	passes := []struct {
+		name    string
+		enabled bool
+		run     func() error
+	}{
+		{
+			name:    "syncing anime list progress",
+			enabled: c.dep.Config.SyncProgress && c.dep.AnimeListWriter != nil,
+			run:     func() error { return c.SyncProgress(ctx, entries) },
+		},
+		{
+			name:    "processing completed torrents",
+			enabled: c.hasCompletionWatcher(),
+			run:     func() error { return c.ProcessCompleted(ctx, entries) },
+		},
+		{
+			name:    "watching stalled torrents",
+			enabled: c.dep.Config.StalledTimeout > 0,
+			run:     func() error { return c.WatchStalled(ctx, entries) },
+		},
+		{
+			name:    "skipping extras",
+			enabled: c.dep.Config.SkipExtras,
+			run:     func() error { return c.SkipExtras(ctx, entries) },
+		},
+		{
+			name:    "replacing upgraded torrents",
+			enabled: c.dep.Config.ReplaceUpgrades,
+			run:     func() error { return c.ReplaceUpgraded(ctx, entries) },
+		},
+		{
+			name:    "removing episodes replaced by batches",
+			enabled: c.dep.Config.BatchRemoveReplaced,
+			run:     func() error { return c.RemoveReplacedEpisodes(ctx, entries) },
+		},
+		{
+			name:    "managing torrent lifecycle",
+			enabled: c.dep.Config.hasLifecycle(),
+			run:     func() error { return c.ManageLifecycle(ctx, entries, removed) },
+		},
+	}
+
+	for _, pass := range passes {
+		if !pass.enabled {
+			continue
+		}
+
+		err := pass.run()
+		switch {
+		case isFatalError(err):
+			return fmt.Errorf("%s: %w", pass.name, err)
+		case err != nil:
+			log.
+				Error().
+				Err(err).
+				Msgf("failed %s, continuing discovery", pass.name)
+		}
+	}
+
+	return nil
+}
+
+// isFatalError returns true for errors stopping the whole discovery, like an unreachable torrent client.
+func isFatalError(err error) bool {
+	return errors.Is(err, torrentclient.ErrUnauthorized) ||
+		errors.Is(err, torrentclient.ErrUnavailable) ||
+		errors.Is(err, context.Canceled)
+}
+
+// filterEpisodes will only return ParsedNyaa entries with episodes missing from covered.
+// Results must be sorted, so batches containing previously selected releases replace them.
+// Higher versions of downloaded episodes, like 05v2, are kept as upgrades, once per episode.
+// It also returns the latest selected tag.
 func filterEpisodes(
 	results []parser.ParsedNyaa,
-	initialTag tags.Tag,
+	covered tags.EpisodeSet,
+	downloaded downloadedEpisodes,
 	filterData *FilterData,
 ) ([]parser.ParsedNyaa, tags.Tag) {
 	out := make([]parser.ParsedNyaa, 0, len(results))
+	upgraded := make(map[string]struct{})
 
 	var latestDetectedTag tags.Tag
 
 	for _, nyaaEntry := range results {
 		currentTag := nyaaEntry.ExtractedMetadata.Tag
 
-		if tagCompare(currentTag, initialTag) <= 0 || tagCompare(currentTag, latestDetectedTag) <= 0 {
+		if downloaded.isUpgrade(nyaaEntry) {
+			if _, ok := upgraded[currentTag.String()]; !ok {
+				upgraded[currentTag.String()] = struct{}{}
+				filterData.UpgradeCount++
+				out = append(out, nyaaEntry)
+				continue
+			}
+		}
+
+		if covered.Contains(currentTag) {
 			filterData.DiscardReason[DiscardReasonOlderEpisode]++
 			continue
 		}
 
-		if !latestDetectedTag.IsZero() {
-			if latestDetectedTag.IsMultiEpisode() && latestDetectedTag.Contains(currentTag) {
-				filterData.DiscardReason[DiscardReasonOlderEpisode]++
-				continue
-			}
-
-			// This scenario can happen when we are filtering for batches, and the subsequent batch contains the previous batch.
-			// Example: S01E01-13, followed by S01.
-			// This happens because S01E01-13 < S01, so S01 comes afterwards. But S01 contains the previous tag.
-			if currentTag.IsMultiEpisode() && currentTag.Contains(latestDetectedTag) {
-				out = utils.Filter(out, func(previous parser.ParsedNyaa) bool {
-					if currentTag.Contains(previous.ExtractedMetadata.Tag) {
-						filterData.DiscardReason[DiscardReasonOlderEpisode]++
-						return false
-					}
-
-					return true
-				})
-			}
+		// This scenario can happen when we are filtering for batches, and the subsequent batch contains the previous batch.
+		// Example: S01E01-13, followed by S01.
+		// This happens because S01E01-13 < S01, so S01 comes afterwards. But S01 contains the previous tag.
+		if currentTag.IsMultiEpisode() {
+			current := tags.NewEpisodeSet(currentTag)
+			out = utils.Filter(out, func(previous parser.ParsedNyaa) bool {
+				if current.Contains(previous.ExtractedMetadata.Tag) {
+					filterData.DiscardReason[DiscardReasonOlderEpisode]++
+					return false
+				}
+
+				return true
+			})
 		}
 
-		latestDetectedTag = currentTag
+		covered = covered.Union(tags.NewEpisodeSet(currentTag))
+		if tagCompare(currentTag, latestDetectedTag) > 0 {
+			latestDetectedTag = currentTag
+		}
 		out = append(out, nyaaEntry)
 	}
 
 	return out, latestDetectedTag
 }
 
+// parseResults parses the Nyaa results, converting absolute episode numbers into season episode numbers.
+// Movies, and other single releases, have no season or episode tag, and specials are always season zero.
 func parseResults(entry animelist.Entry, results []nyaa.Item) []parser.ParsedNyaa {
-	return utils.Map(results, func(item nyaa.Item) parser.ParsedNyaa {
+	mapping := episodeMapping(entry)
+
+	parsed := utils.Map(results, func(item nyaa.Item) parser.ParsedNyaa {
 		return parser.NewParsedNyaa(entry, item)
 	})
+
+	absoluteSources := absoluteNumberingSources(mapping, parsed)
+
+	for i := range parsed {
+		meta := &parsed[i].ExtractedMetadata
+
+		switch {
+		case isSingleRelease(entry):
+			meta.Tag = tags.Tag{}
+		case entry.Format == animelist.MediaFormatSpecial:
+			meta.Tag.Seasons = []int{0}
+		case absoluteSources[meta.Source] && !parser.HasSeason(parsed[i].NyaaTorrent.Title):
+			meta.Tag = mapping.FromAbsolute(meta.Tag)
+		default:
+			meta.Tag = mapping.ToSeasonal(meta.Tag)
+		}
+	}
+
+	return parsed
+}
+
+// absoluteNumberingSources returns the release groups using absolute episode numbers for the entry.
+// A group uses absolute numbering when any of it's releases without a season has an episode bigger than the season episode count.
+// Example: with seasons [12, 13], a group releasing Show - 24 also numbers the first episode of season 2 as Show - 13.
+func absoluteNumberingSources(mapping tags.EpisodeMapping, results []parser.ParsedNyaa) map[string]bool {
+	sources := make(map[string]bool)
+
+	for _, result := range results {
+		if mapping.IsAbsolute(result.ExtractedMetadata.Tag) && !parser.HasSeason(result.NyaaTorrent.Title) {
+			sources[result.ExtractedMetadata.Source] = true
+		}
+	}
+
+	return sources
 }
 
 // sortResults will digest the raw data from Nyaa into a parsed metadata struct `ParsedNyaa`.
@@ -161,6 +305,12 @@ func sortResults(entry animelist.Entry, results []parser.ParsedNyaa) []parser.Pa
 			return cmp < 0
 		}
 
+		// Then the highest release version, like 05v2.
+		cmp = releaseVersion(second.ExtractedMetadata.Version) - releaseVersion(first.ExtractedMetadata.Version)
+		if cmp != 0 {
+			return cmp < 0
+		}
+
 		// Then title similarity.
 		titleSimilarityI := utils.Max(utils.Map(entry.Titles, func(curTitle string) float64 {
 			return utils.CalculateTextSimilarity(curTitle, first.ExtractedMetadata.Title, ignoreCharset)
@@ -190,16 +340,26 @@ func sortResults(entry animelist.Entry, results []parser.ParsedNyaa) []parser.Pa
 }
 
 // filterRelevantResults is responsible for filtering and ordering the raw Nyaa feed into valid downloadable torrents.
+// downloaded is used for detecting upgrades of already downloaded episodes, and for the batch policies.
 func filterRelevantResults(
+	config Config,
 	entry animelist.Entry,
 	results []parser.ParsedNyaa,
 	latestTag tags.Tag,
+	downloaded downloadedEpisodes,
 	filterData *FilterData,
 ) []parser.ParsedNyaa {
 	results = slices.Clone(results)
 	// Requires sorted input, since we use tag progression.
 	results = sortResults(entry, results)
 
+	downloadedSet := downloaded.episodeSet().Union(tags.NewEpisodeSet(latestTag))
+	filterData.Downloaded = downloadedSet.String()
+
+	covered := episodeCoverage(downloadedSet)
+
+	var replacements []parser.ParsedNyaa
+
 	if latestTag.IsZero() && entry.AiringStatus == animelist.AiringStatusAired {
 		batchResults := utils.Filter(results, func(entry parser.ParsedNyaa) bool {
 			return entry.ExtractedMetadata.Tag.IsMultiEpisode()
@@ -209,30 +369,159 @@ func filterRelevantResults(
 			results = batchResults
 		}
 	} else {
+		if config.BatchReplaceEpisodes && entry.AiringStatus == animelist.AiringStatusAired {
+			replacements = replacementBatches(config, results, downloaded)
+			for _, replacement := range replacements {
+				covered = covered.Union(tags.NewEpisodeSet(replacement.ExtractedMetadata.Tag))
+			}
+		}
+
 		// Remove batches when there are latest tags, avoid episode download duplication.
+		// Partial batches are kept for filling gaps, if configured.
 		results = utils.Filter(results, func(entry parser.ParsedNyaa) bool {
-			return !entry.ExtractedMetadata.Tag.IsMultiEpisode()
+			tag := entry.ExtractedMetadata.Tag
+			return !tag.IsMultiEpisode() || config.BatchFillGaps && fillsGap(tag, downloadedSet)
 		})
 	}
 
-	results, latestDetectedTag := filterEpisodes(results, latestTag, filterData)
+	results, latestDetectedTag := filterEpisodes(results, covered, downloaded, filterData)
+	for _, replacement := range replacements {
+		if tagCompare(replacement.ExtractedMetadata.Tag, latestDetectedTag) > 0 {
+			latestDetectedTag = replacement.ExtractedMetadata.Tag
+		}
+	}
 	filterData.NewLatestTag = latestDetectedTag
+	filterData.ReplacementCount = len(replacements)
 
-	return results
+	return append(replacements, results...)
+}
+
+// replacementBatches returns complete batches for replacing downloaded single episodes of aired shows.
+// Batches must start at the first episode and contain every downloaded single episode of their seasons, like S01 or S01E01-12.
+// Seasons with a downloaded batch are not replaced again.
+func replacementBatches(config Config, results []parser.ParsedNyaa, downloaded downloadedEpisodes) []parser.ParsedNyaa {
+	var singles, batches []tags.Tag
+
+	for _, d := range downloaded {
+		switch {
+		case d.Tag.IsZero():
+		case d.Tag.IsMultiEpisode():
+			batches = append(batches, d.Tag)
+		default:
+			singles = append(singles, d.Tag)
+		}
+	}
+
+	var out []parser.ParsedNyaa
+
+	for _, release := range results {
+		tag := release.ExtractedMetadata.Tag
+		if tag.IsZero() || !tag.IsMultiEpisode() || tag.FirstEpisode() > 1 || !isReplacementSource(config, release) {
+			continue
+		}
+
+		if slices.ContainsFunc(batches, func(batch tags.Tag) bool { return sharesSeason(batch, tag) }) {
+			continue
+		}
+
+		batch := tags.NewEpisodeSet(tag)
+		seasonSingles := utils.Filter(singles, func(single tags.Tag) bool { return sharesSeason(single, tag) })
+		if len(seasonSingles) == 0 || !tags.NewEpisodeSet(seasonSingles...).Difference(batch).IsEmpty() {
+			continue
+		}
+
+		// Releases of the same batch are sorted by preference, so only the first one is kept.
+		if slices.ContainsFunc(out, func(previous parser.ParsedNyaa) bool {
+			return tags.NewEpisodeSet(previous.ExtractedMetadata.Tag).Contains(tag)
+		}) {
+			continue
+		}
+
+		// Sorted results have smaller batches first, like S01E01-12 before S01, the bigger batch replaces them.
+		out = utils.Filter(out, func(previous parser.ParsedNyaa) bool {
+			return !batch.Contains(previous.ExtractedMetadata.Tag)
+		})
+
+		release.Replacement = true
+		out = append(out, release)
+	}
+
+	return out
+}
+
+// isReplacementSource returns true when the release media source is allowed for replacing single episodes.
+func isReplacementSource(config Config, release parser.ParsedNyaa) bool {
+	if len(config.BatchReplaceSources) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(config.BatchReplaceSources, func(source string) bool {
+		return strings.EqualFold(source, release.ExtractedMetadata.MediaSource)
+	})
+}
+
+// fillsGap returns true for partial batches within the downloaded episodes of a season, like E02-07 when E01 and E08 were downloaded.
+// Batches going past the last downloaded episode are left for single episodes.
+// They don't replace the downloaded single episodes, only batches returned by replacementBatches do.
+func fillsGap(tag tags.Tag, downloaded tags.EpisodeSet) bool {
+	if len(tag.Seasons) != 1 || len(tag.Episodes) == 0 {
+		return false
+	}
+
+	ranges := downloaded.Ranges(tag.FirstSeason())
+	if len(ranges) == 0 {
+		return false
+	}
+
+	last := ranges[0].Last
+	for _, r := range ranges {
+		last = max(last, r.Last)
+	}
+
+	return tag.LastEpisode() <= last
 }
 
 type (
 	DiscardReason string
 
 	FilterData struct {
-		LatestTag     tags.Tag               `json:"latest_tag,omitzero"`
-		NewLatestTag  tags.Tag               `json:"new_latest_tag,omitzero"`
-		SearchCount   int                    `json:"search_count,omitempty"`
-		NewCount      int                    `json:"new_count,omitempty"`
-		DiscardReason map[DiscardReason]uint `json:"discard_reason,omitempty"`
+		LatestTag    tags.Tag `json:"latest_tag,omitzero"`
+		Downloaded   string   `json:"downloaded,omitempty"`
+		NewLatestTag tags.Tag `json:"new_latest_tag,omitzero"`
+		SearchCount  int      `json:"search_count,omitempty"`
+		NewCount     int      `json:"new_count,omitempty"`
+		UpgradeCount int      `json:"upgrade_count,omitempty"`
+		// ReplacementCount is the number of batches replacing downloaded single episodes.
+		ReplacementCount int                    `json:"replacement_count,omitempty"`
+		DiscardReason    map[DiscardReason]uint `json:"discard_reason,omitempty"`
+		// TitleMismatches are the closest releases discarded by title, with their match score.
+		TitleMismatches []TitleMismatch `json:"title_mismatches,omitempty"`
+	}
+
+	TitleMismatch struct {
+		Release string  `json:"release"`
+		Title   string  `json:"title"`
+		Score   float64 `json:"score"`
 	}
 )
 
+// maxTitleMismatches limits how many title mismatches are kept for explaining discarded releases.
+const maxTitleMismatches = 5
+
+// addTitleMismatch counts a title mismatch, keeping only the highest scoring ones for the explanation.
+func (f *FilterData) addTitleMismatch(mismatch TitleMismatch) {
+	f.DiscardReason[DiscardReasonTitleMismatch]++
+
+	f.TitleMismatches = append(f.TitleMismatches, mismatch)
+	slices.SortStableFunc(f.TitleMismatches, func(a, b TitleMismatch) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if len(f.TitleMismatches) > maxTitleMismatches {
+		f.TitleMismatches = f.TitleMismatches[:maxTitleMismatches]
+	}
+}
+
 const (
 	DiscardReasonNotBatch              DiscardReason = "not_batch"
 	DiscardReasonNoSeeder              DiscardReason = "no_seeder"
@@ -240,6 +529,11 @@ const (
 	DiscardReasonPublishedDateMismatch DiscardReason = "publish_date_mismatch"
 	DiscardReasonEpisodeCountMismatch  DiscardReason = "episode_count_mismatch"
 	DiscardReasonTitleMismatch         DiscardReason = "title_mismatch"
+	DiscardReasonAheadOfProgress       DiscardReason = "ahead_of_progress"
+	DiscardReasonWatched               DiscardReason = "already_watched"
+	DiscardReasonAlreadyDownloaded     DiscardReason = "already_downloaded"
+	DiscardReasonNotBestRelease        DiscardReason = "not_best_release"
+	DiscardReasonBlocklisted           DiscardReason = "blocklisted"
 )
 
 func (c *Controller) NyaaSearch(
@@ -257,9 +551,11 @@ func (c *Controller) NyaaSearch(
 		")", " ",
 	)
 
+	titles := entryTitles(entry, c.dep.Config.Aliases)
+
 	// Build search query for Nyaa.
-	// For title we filter for english and original titles.
-	sanitizedTitles := utils.Transform(entry.Titles,
+	// For title we filter for english and original titles, their aliases and synonyms.
+	sanitizedTitles := utils.Transform(titles,
 		strings.ToLower,
 		parser.StripTitle,
 		parser.StripSubtitle,
@@ -284,7 +580,7 @@ func (c *Controller) NyaaSearch(
 	}
 
 	entries = utils.Filter(entries,
-		filterMetadata(entry, filterData),
+		filterMetadata(c.dep.Config, entry, titles, filterData),
 	)
 
 	if len(entries) == 0 {
@@ -296,6 +592,79 @@ func (c *Controller) NyaaSearch(
 	return entries, nil
 }
 
+// filterEpisodeResults returns the episodes and batches to download, based on the latest downloaded tag.
+func (c *Controller) filterEpisodeResults(
+	ctx context.Context,
+	entry animelist.Entry,
+	results []nyaa.Item,
+	filterData *FilterData,
+) ([]parser.ParsedNyaa, error) {
+	latestTag, downloaded, err := c.findLatestTag(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("finding latest anime season episode tag: %w", err)
+	}
+
+	filterData.LatestTag = latestTag
+
+	parsedTorrents := parseResults(entry, results)
+	parsedTorrents = utils.Filter(parsedTorrents, filterProgress(c.dep.Config, entry, filterData))
+	parsedTorrents = filterRelevantResults(c.dep.Config, entry, parsedTorrents, latestTag, downloaded, filterData)
+
+	if c.dep.Config.isBatchOnly(entry) {
+		parsedTorrents = utils.Filter(parsedTorrents, func(entry parser.ParsedNyaa) bool {
+			if !entry.ExtractedMetadata.Tag.IsMultiEpisode() {
+				filterData.DiscardReason[DiscardReasonNotBatch]++
+				return false
+			}
+
+			return true
+		})
+	}
+
+	return parsedTorrents, nil
+}
+
+// isSingleRelease returns true for entries downloaded as a single release without an episode tag, like movies.
+// Single episode OVAs and ONAs are released like movies, often without an episode number, like Show OVA [1080p].
+// OVAs and ONAs with more episodes are released like TV shows, so they use the episode rules.
+func isSingleRelease(entry animelist.Entry) bool {
+	switch entry.Format {
+	case animelist.MediaFormatMovie:
+		return true
+	case animelist.MediaFormatOVA, animelist.MediaFormatONA:
+		return entry.NumEpisodes == 1
+	default:
+		return false
+	}
+}
+
+// filterMovieResults returns the best release for a movie or single release, unless it was already downloaded or imported from disk.
+// Movies have no episodes, so their releases are only ranked by title similarity, resolution and seeders.
+func (c *Controller) filterMovieResults(
+	ctx context.Context,
+	entry animelist.Entry,
+	results []nyaa.Item,
+	filterData *FilterData,
+) ([]parser.ParsedNyaa, error) {
+	torrents, err := c.listEntryTorrents(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("listing movie torrents: %w", err)
+	}
+
+	if len(torrents) > 0 || len(c.ownedEpisodes(entry)) > 0 {
+		filterData.DiscardReason[DiscardReasonAlreadyDownloaded] += uint(len(results))
+		return nil, nil
+	}
+
+	parsedTorrents := sortResults(entry, parseResults(entry, results))
+	if len(parsedTorrents) > 1 {
+		filterData.DiscardReason[DiscardReasonNotBestRelease] += uint(len(parsedTorrents) - 1)
+		parsedTorrents = parsedTorrents[:1]
+	}
+
+	return parsedTorrents, nil
+}
+
 // DiscoverEntry receives an anime list entry and fetches the anime feed, looking for new content.
 // It returns the latest discovered tag, whether new episodes were found, and any error.
 func (c *Controller) DiscoverEntry(ctx context.Context, entry animelist.Entry) (bool, error) {
@@ -306,12 +675,16 @@ func (c *Controller) DiscoverEntry(ctx context.Context, entry animelist.Entry) (
 		DiscardReason: make(map[DiscardReason]uint),
 	}
 
+	if err := c.migrateTitleTag(ctx, entry); err != nil {
+		return false, fmt.Errorf("migrating show title tag: %w", err)
+	}
+
 	searchResults, err := c.NyaaSearch(ctx, entry, filterData)
 	if err != nil {
 		return false, fmt.Errorf("searching torrent for anime: %w", err)
 	}
 
-	// Remove results without seeders.
+	// Remove results without seeders, or blocklisted.
 	torrentResults := utils.Filter(searchResults,
 		func(e nyaa.Item) bool {
 			if e.Seeders == 0 {
@@ -319,6 +692,11 @@ func (c *Controller) DiscoverEntry(ctx context.Context, entry animelist.Entry) (
 				return false
 			}
 
+			if c.dep.Blocklist != nil && e.InfoHash != "" && c.dep.Blocklist.IsBlocked(e.InfoHash) {
+				filterData.DiscardReason[DiscardReasonBlocklisted]++
+				return false
+			}
+
 			return true
 		},
 	)
@@ -332,16 +710,17 @@ func (c *Controller) DiscoverEntry(ctx context.Context, entry animelist.Entry) (
 		return false, nil
 	}
 
-	latestTag, err := c.findLatestTag(ctx, entry)
+	var parsedTorrents []parser.ParsedNyaa
+
+	if isSingleRelease(entry) {
+		parsedTorrents, err = c.filterMovieResults(ctx, entry, torrentResults, filterData)
+	} else {
+		parsedTorrents, err = c.filterEpisodeResults(ctx, entry, torrentResults, filterData)
+	}
 	if err != nil {
-		return false, fmt.Errorf("finding latest anime season episode tag: %w", err)
+		return false, err
 	}
 
-	filterData.LatestTag = latestTag
-
-	parsedTorrents := parseResults(entry, torrentResults)
-	parsedTorrents = filterRelevantResults(entry, parsedTorrents, latestTag, filterData)
-
 	foundNewEpisodes := len(parsedTorrents) > 0
 
 	for _, episodeTorrent := range parsedTorrents {
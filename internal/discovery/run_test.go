@@ -1,6 +1,7 @@
 package discovery
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -8,14 +9,17 @@ import (
 	"github.com/sonalys/animeman/internal/integrations/nyaa"
 	"github.com/sonalys/animeman/internal/parser"
 	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/internal/utils"
 	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 	"github.com/stretchr/testify/require"
 )
 
 func Test_filterEpisodes(t *testing.T) {
 	type args struct {
-		list      []parser.ParsedNyaa
-		latestTag tags.Tag
+		list       []parser.ParsedNyaa
+		latestTag  tags.Tag
+		downloaded []tags.Tag
 	}
 	tests := []struct {
 		name string
@@ -88,10 +92,56 @@ func Test_filterEpisodes(t *testing.T) {
 			},
 			want: []parser.ParsedNyaa{},
 		},
+		{
+			name: "missing episode",
+			args: args{
+				downloaded: []tags.Tag{
+					{Seasons: []int{1}, Episodes: []float64{1, 10}},
+					tags.SeasonEpisode(1, 12),
+				},
+				list: []parser.ParsedNyaa{
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 10)}},
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 11)}},
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 12)}},
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 13)}},
+				},
+			},
+			want: []parser.ParsedNyaa{
+				{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 11)}},
+				{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 13)}},
+			},
+		},
+		{
+			name: "tracking mid season",
+			args: args{
+				downloaded: []tags.Tag{tags.SeasonEpisode(2, 5)},
+				list: []parser.ParsedNyaa{
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 3)}},
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(2, 4)}},
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(2, 6)}},
+				},
+			},
+			want: []parser.ParsedNyaa{
+				{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(2, 6)}},
+			},
+		},
+		{
+			name: "batch replaces selected episodes",
+			args: args{
+				list: []parser.ParsedNyaa{
+					{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, 1)}},
+					{ExtractedMetadata: parser.Metadata{Tag: tags.Tag{Seasons: []int{1}, Episodes: []float64{1, 12}}}},
+				},
+			},
+			want: []parser.ParsedNyaa{
+				{ExtractedMetadata: parser.Metadata{Tag: tags.Tag{Seasons: []int{1}, Episodes: []float64{1, 12}}}},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _ := filterEpisodes(tt.args.list, tt.args.latestTag, &FilterData{DiscardReason: make(map[DiscardReason]uint)}); !reflect.DeepEqual(got, tt.want) {
+			covered := episodeCoverage(tags.NewEpisodeSet(append(tt.args.downloaded, tt.args.latestTag)...))
+			if got, _ := filterEpisodes(tt.args.list, covered, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)}); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("filterEpisodes() = %v, want %v", got, tt.want)
 			}
 		})
@@ -147,7 +197,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 	}
 
 	t.Run("empty", func(t *testing.T) {
-		got := filterRelevantResults(animelist.Entry{}, []parser.ParsedNyaa{}, tags.Zero, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, animelist.Entry{}, []parser.ParsedNyaa{}, tags.Zero, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 		require.Empty(t, got)
 	})
 
@@ -159,7 +209,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(animelist.Entry{}, parsed, tags.Zero, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, animelist.Entry{}, parsed, tags.Zero, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, len(input))
 		for i := 1; i < len(got); i++ {
@@ -178,11 +228,36 @@ func Test_filterNyaaFeed(t *testing.T) {
 		parsedTorrents := parseResults(animelist.Entry{}, input)
 		latestTag := tags.SeasonEpisode(3, 2)
 
-		got := filterRelevantResults(entry, parsedTorrents, latestTag, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, entry, parsedTorrents, latestTag, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Equal(t, parsedTorrents[:1], got)
 	})
 
+	t.Run("airing: with upgraded release", func(t *testing.T) {
+		input := []nyaa.Item{
+			{Title: "Show3: S03E03"},
+			{Title: "Show3: S03E02v2"},
+			{Title: "Show3: S03E02v2"},
+			{Title: "Show3: S03E02"},
+			{Title: "Show3: S03E01v2"},
+		}
+
+		parsed := parseResults(animelist.Entry{}, input)
+		downloaded := downloadedEpisodes{
+			"S3E1": {Version: 2, Hashes: []string{"e1v2"}},
+			"S3E2": {Version: 1, Hashes: []string{"e2"}},
+		}
+		filterData := &FilterData{DiscardReason: make(map[DiscardReason]uint)}
+
+		got := filterRelevantResults(Config{}, animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), downloaded, filterData)
+
+		require.Len(t, got, 2)
+		require.Equal(t, tags.SeasonEpisode(3, 2), got[0].ExtractedMetadata.Tag)
+		require.Equal(t, 2, got[0].ExtractedMetadata.Version)
+		require.Equal(t, tags.SeasonEpisode(3, 3), got[1].ExtractedMetadata.Tag)
+		require.Equal(t, 1, filterData.UpgradeCount)
+	})
+
 	t.Run("airing: with repeated tag", func(t *testing.T) {
 		input := []nyaa.Item{
 			{Title: "Show3: S03E02"},
@@ -191,7 +266,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(animelist.Entry{}, parsed, tags.SeasonEpisode(3, 1), &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, animelist.Entry{}, parsed, tags.SeasonEpisode(3, 1), nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 1)
 		require.Equal(t, parsed[0:1], got)
@@ -206,7 +281,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 1)
 		require.Equal(t, parsed[1:2], got)
@@ -220,7 +295,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 1)
 		require.Equal(t, parsed[:1], got)
@@ -234,7 +309,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(newEntry(animelist.AiringStatusAired), parsed, tags.Zero, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, newEntry(animelist.AiringStatusAired), parsed, tags.Zero, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Equal(t, parsed[2:], got)
 	})
@@ -246,7 +321,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(newEntry(animelist.AiringStatusAired), parsed, tags.Zero, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, newEntry(animelist.AiringStatusAired), parsed, tags.Zero, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Equal(t, parsed[1:], got)
 	})
@@ -258,7 +333,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(newEntry(animelist.AiringStatusAired), parsed, tags.Zero, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, newEntry(animelist.AiringStatusAired), parsed, tags.Zero, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 1)
 		require.Equal(t, parsed[1:], got)
@@ -272,7 +347,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(newEntry(animelist.AiringStatusAired), parsed, tags.SeasonEpisode(3, 2), &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, newEntry(animelist.AiringStatusAired), parsed, tags.SeasonEpisode(3, 2), nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 1)
 		require.Equal(t, parsed[:1], got)
@@ -286,7 +361,7 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, animelist.Entry{}, parsed, tags.SeasonEpisode(3, 2), nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 1)
 		require.Equal(t, parsed[1:2], got)
@@ -300,8 +375,340 @@ func Test_filterNyaaFeed(t *testing.T) {
 		}
 
 		parsed := parseResults(animelist.Entry{}, input)
-		got := filterRelevantResults(newEntry(animelist.AiringStatusAired), parsed, tags.Zero, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		got := filterRelevantResults(Config{}, newEntry(animelist.AiringStatusAired), parsed, tags.Zero, nil, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
 
 		require.Len(t, got, 3)
 	})
 }
+
+func Test_parseResults_formats(t *testing.T) {
+	input := []nyaa.Item{{Title: "[Group] Show S02 - 03 [1080p]"}}
+
+	movie := parseResults(animelist.Entry{Format: animelist.MediaFormatMovie}, input)
+	require.True(t, movie[0].ExtractedMetadata.Tag.IsZero())
+
+	special := parseResults(animelist.Entry{Format: animelist.MediaFormatSpecial}, input)
+	require.Equal(t, tags.SeasonEpisode(0, 3), special[0].ExtractedMetadata.Tag)
+
+	tv := parseResults(animelist.Entry{Format: animelist.MediaFormatTV}, input)
+	require.Equal(t, tags.SeasonEpisode(2, 3), tv[0].ExtractedMetadata.Tag)
+
+	ova := parseResults(animelist.Entry{Format: animelist.MediaFormatOVA, NumEpisodes: 1}, input)
+	require.True(t, ova[0].ExtractedMetadata.Tag.IsZero())
+
+	ona := parseResults(animelist.Entry{Format: animelist.MediaFormatONA, NumEpisodes: 6}, input)
+	require.Equal(t, tags.SeasonEpisode(2, 3), ona[0].ExtractedMetadata.Tag)
+}
+
+func Test_isSingleRelease(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry animelist.Entry
+		want  bool
+	}{
+		{name: "movie", entry: animelist.Entry{Format: animelist.MediaFormatMovie}, want: true},
+		{name: "single episode ova", entry: animelist.Entry{Format: animelist.MediaFormatOVA, NumEpisodes: 1}, want: true},
+		{name: "single episode ona", entry: animelist.Entry{Format: animelist.MediaFormatONA, NumEpisodes: 1}, want: true},
+		{name: "ova series", entry: animelist.Entry{Format: animelist.MediaFormatOVA, NumEpisodes: 6}, want: false},
+		{name: "ona with unknown episodes", entry: animelist.Entry{Format: animelist.MediaFormatONA}, want: false},
+		{name: "tv", entry: animelist.Entry{Format: animelist.MediaFormatTV, NumEpisodes: 1}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isSingleRelease(tt.entry))
+		})
+	}
+}
+
+func Test_filterMovieResults(t *testing.T) {
+	entry := animelist.Entry{
+		Titles: []string{"Movie"},
+		Format: animelist.MediaFormatMovie,
+	}
+	results := []nyaa.Item{
+		{Title: "[Group] Movie [720p]", Seeders: 10},
+		{Title: "[Group] Movie [1080p]", Seeders: 5},
+		{Title: "[Group] Movie [1080p]", Seeders: 1},
+	}
+
+	t.Run("best release", func(t *testing.T) {
+		c := New(Dependencies{TorrentClient: &fakeTorrentClient{}})
+		filterData := &FilterData{DiscardReason: make(map[DiscardReason]uint)}
+
+		got, err := c.filterMovieResults(t.Context(), entry, results, filterData)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, 1080, got[0].ExtractedMetadata.VerticalResolution)
+		require.Equal(t, 5, got[0].NyaaTorrent.Seeders)
+		require.Equal(t, []string{"!movie"}, got[0].ExtractedMetadata.BuildTorrentTags())
+		require.Equal(t, uint(2), filterData.DiscardReason[DiscardReasonNotBestRelease])
+	})
+
+	t.Run("already downloaded", func(t *testing.T) {
+		torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!movie"}},
+		}}
+		c := New(Dependencies{TorrentClient: torrentClient})
+		filterData := &FilterData{DiscardReason: make(map[DiscardReason]uint)}
+
+		got, err := c.filterMovieResults(t.Context(), entry, results, filterData)
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.Equal(t, uint(3), filterData.DiscardReason[DiscardReasonAlreadyDownloaded])
+	})
+}
+
+func Test_findLatestTag_specials(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "S1E5"}},
+		{Hash: "b", Tags: []string{"!show", "S0E2"}},
+	}}
+	c := New(Dependencies{TorrentClient: torrentClient})
+
+	got, _, err := c.findLatestTag(t.Context(), animelist.Entry{Titles: []string{"Show"}})
+	require.NoError(t, err)
+	require.Equal(t, tags.SeasonEpisode(1, 5), got)
+
+	got, _, err = c.findLatestTag(t.Context(), animelist.Entry{Titles: []string{"Show"}, Format: animelist.MediaFormatSpecial})
+	require.NoError(t, err)
+	require.Equal(t, tags.SeasonEpisode(0, 2), got)
+}
+
+func Test_listEntryTorrents(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E05", "am:show:anilist:1"}},
+		{Hash: "b", Tags: []string{"!show", "am:ep:S01E06"}},
+		{Hash: "c", Tags: []string{"!show", "am:ep:S00E01", "am:show:anilist:2"}},
+		{Hash: "d", Tags: []string{"!renamed", "am:ep:S01E01", "am:show:anilist:1"}},
+	}}
+	c := New(Dependencies{TorrentClient: torrentClient})
+
+	entry := animelist.Entry{Titles: []string{"Show", "show"}, IDs: animelist.IDs{AniList: 1}}
+	got, err := c.listEntryTorrents(t.Context(), entry)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "d", "b"}, utils.Map(got, func(t torrentclient.Torrent) string { return t.Hash }))
+
+	t.Run("entry gains an identifier after tagging", func(t *testing.T) {
+		torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!old title", "am:ep:S01E05", "am:show:mal:5"}},
+			{Hash: "b", Tags: []string{"!show", "am:ep:S01E06", "am:show:mal:5"}},
+			{Hash: "c", Tags: []string{"!show", "am:ep:S01E01", "am:show:mal:6"}},
+		}}
+		c := New(Dependencies{TorrentClient: torrentClient})
+
+		// Tagged while the entry only had it's MAL identifier, so the entry key changed from mal:5 to anilist:1.
+		entry := animelist.Entry{Titles: []string{"Show"}, IDs: animelist.IDs{AniList: 1, MAL: 5}}
+		got, err := c.listEntryTorrents(t.Context(), entry)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, utils.Map(got, func(t torrentclient.Torrent) string { return t.Hash }))
+	})
+}
+
+func Test_TorrentRegenerateTags(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Category: "anime", Name: "[Group] Show - 05v2 [1080p].mkv"},
+		{Hash: "b", Category: "anime", Tags: []string{"!show", "S1E6", "seen"}},
+		{Hash: "c", Category: "anime", Tags: []string{"!show", "am:ep:S01E07"}},
+		{Hash: "d", Category: "other", Tags: []string{"!show", "S1E8"}},
+	}}
+	c := New(Dependencies{TorrentClient: torrentClient, Config: Config{Category: "anime"}})
+
+	require.NoError(t, c.TorrentRegenerateTags(t.Context()))
+
+	tagsByHash := make(map[string][]string)
+	for _, torrent := range torrentClient.torrents {
+		tagsByHash[torrent.Hash] = torrent.Tags
+	}
+
+	require.Equal(t, map[string][]string{
+		"a": {"!show", "am:ep:S01E05", "am:v:2"},
+		"b": {"!show", "am:ep:S01E06", "seen"},
+		"c": {"!show", "am:ep:S01E07"},
+		"d": {"!show", "S1E8"},
+	}, tagsByHash)
+}
+
+func Test_filterRelevantResults_batchPolicies(t *testing.T) {
+	aired := animelist.NewEntry(nil, animelist.ListStatusWatching, animelist.AiringStatusAired, time.Now(), time.Now(), 0, nil)
+	downloaded := downloadedEpisodes{
+		"S1E1": {Tag: tags.SeasonEpisode(1, 1), Version: 1, Hashes: []string{"e1"}},
+		"S1E2": {Tag: tags.SeasonEpisode(1, 2), Version: 1, Hashes: []string{"e2"}},
+		"S1E5": {Tag: tags.SeasonEpisode(1, 5), Version: 1, Hashes: []string{"e5"}},
+	}
+	input := []nyaa.Item{
+		{Title: "[Group] Show - S01E01-06 [WEB 1080p]"},
+		{Title: "[Group] Show - S01E06"},
+		{Title: "[Group] Show - S01E01-12 [BD 1080p]", Seeders: 1},
+		{Title: "[Group] Show - S01E01-12 [WEB 1080p]", Seeders: 2},
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{
+			name: "disabled",
+			want: []string{"[Group] Show - S01E06"},
+		},
+		{
+			name:   "replace episodes",
+			config: Config{BatchReplaceEpisodes: true},
+			want:   []string{"[Group] Show - S01E01-12 [WEB 1080p]"},
+		},
+		{
+			name:   "replace episodes from source",
+			config: Config{BatchReplaceEpisodes: true, BatchReplaceSources: []string{"bd"}},
+			want:   []string{"[Group] Show - S01E01-12 [BD 1080p]"},
+		},
+		{
+			name:   "replace episodes from missing source",
+			config: Config{BatchReplaceEpisodes: true, BatchReplaceSources: []string{"DVD"}},
+			want:   []string{"[Group] Show - S01E06"},
+		},
+		{
+			name:   "fill gaps",
+			config: Config{BatchFillGaps: true},
+			want:   []string{"[Group] Show - S01E06"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := parseResults(animelist.Entry{}, input)
+			got := filterRelevantResults(tt.config, aired, parsed, tags.SeasonEpisode(1, 5), downloaded, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+			require.Equal(t, tt.want, utils.Map(got, func(p parser.ParsedNyaa) string { return p.NyaaTorrent.Title }))
+		})
+	}
+
+	t.Run("fill gaps with partial batch", func(t *testing.T) {
+		input := []nyaa.Item{
+			{Title: "[Group] Show - S01E01-04"},
+			{Title: "[Group] Show - S01E01-08"},
+			{Title: "[Group] Show - S01E06"},
+		}
+		parsed := parseResults(animelist.Entry{}, input)
+		config := Config{BatchFillGaps: true}
+
+		got := filterRelevantResults(config, aired, parsed, tags.SeasonEpisode(1, 5), downloaded, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		require.Equal(t, []string{"[Group] Show - S01E06", "[Group] Show - S01E01-04"}, utils.Map(got, func(p parser.ParsedNyaa) string { return p.NyaaTorrent.Title }))
+	})
+
+	t.Run("fill gaps between downloaded episodes", func(t *testing.T) {
+		downloaded := downloadedEpisodes{
+			"S1E1": {Tag: tags.SeasonEpisode(1, 1), Version: 1, Hashes: []string{"e1"}},
+			"S1E8": {Tag: tags.SeasonEpisode(1, 8), Version: 1, Hashes: []string{"e8"}},
+		}
+		parsed := parseResults(animelist.Entry{}, []nyaa.Item{
+			{Title: "[Group] Show - S01E02-07"},
+			{Title: "[Group] Show - S01E02-10"},
+		})
+		config := Config{BatchFillGaps: true, BatchReplaceEpisodes: true}
+
+		got := filterRelevantResults(config, aired, parsed, tags.SeasonEpisode(1, 8), downloaded, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		require.Len(t, got, 1)
+		require.Equal(t, "[Group] Show - S01E02-07", got[0].NyaaTorrent.Title)
+		require.False(t, got[0].Replacement)
+	})
+
+	t.Run("replacement batches are marked", func(t *testing.T) {
+		parsed := parseResults(animelist.Entry{}, input)
+		config := Config{BatchReplaceEpisodes: true}
+
+		got := filterRelevantResults(config, aired, parsed, tags.SeasonEpisode(1, 5), downloaded, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		require.Len(t, got, 1)
+		require.True(t, got[0].Replacement)
+	})
+
+	t.Run("season already has a batch", func(t *testing.T) {
+		downloaded := downloadedEpisodes{
+			"S1E1-12": {Tag: tags.Tag{Seasons: []int{1}, Episodes: []float64{1, 12}}, Version: 1, Hashes: []string{"batch"}},
+			"S1E5":    {Tag: tags.SeasonEpisode(1, 5), Version: 1, Hashes: []string{"e5"}},
+		}
+		parsed := parseResults(animelist.Entry{}, []nyaa.Item{{Title: "[Group] Show - S01 [BD 1080p]"}})
+		config := Config{BatchReplaceEpisodes: true}
+
+		got := filterRelevantResults(config, aired, parsed, tags.SeasonEpisode(1, 12), downloaded, &FilterData{DiscardReason: make(map[DiscardReason]uint)})
+		require.Empty(t, got)
+	})
+}
+
+func Test_RemoveReplacedEpisodes(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1},
+		{Hash: "b", Tags: []string{"!show", "am:ep:S01E01-12", "am:replacement"}, Progress: 1},
+		{Hash: "c", Tags: []string{"!show", "am:ep:S02E03"}, Progress: 1},
+		{Hash: "d", Tags: []string{"!show", "am:ep:S02E01-12", "am:replacement"}, Progress: 0.5},
+	}}
+	entry := animelist.Entry{Titles: []string{"Show"}, NumEpisodes: 12}
+
+	c := New(Dependencies{TorrentClient: torrentClient, Config: Config{BatchRemoveReplaced: true, RemoveKeepFiles: true}})
+
+	// Batches still downloading keep the single episodes.
+	require.NoError(t, c.RemoveReplacedEpisodes(t.Context(), []animelist.Entry{entry}))
+	require.Equal(t, []string{"a"}, torrentClient.deleted)
+	require.False(t, torrentClient.deleteFiles)
+}
+
+func Test_ReplaceUpgraded(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1},
+		{Hash: "b", Tags: []string{"!show", "am:ep:S01E05", "am:v:2"}, Progress: 1},
+		{Hash: "c", Tags: []string{"!show", "am:ep:S01E06"}, Progress: 1},
+		{Hash: "d", Tags: []string{"!show", "am:ep:S01E06", "am:v:2"}, Progress: 0.5},
+	}}
+	entry := animelist.Entry{Titles: []string{"Show"}, NumEpisodes: 12}
+
+	c := New(Dependencies{TorrentClient: torrentClient, Config: Config{ReplaceUpgrades: true, RemoveKeepFiles: true}})
+
+	// Upgrades still downloading keep the previous release.
+	require.NoError(t, c.ReplaceUpgraded(t.Context(), []animelist.Entry{entry}))
+	require.Equal(t, []string{"a"}, torrentClient.deleted)
+	require.False(t, torrentClient.deleteFiles)
+}
+
+func Test_manageTorrents(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E01"}, State: torrentclient.StateStalled, AddedOn: time.Now().Add(-2 * time.Hour)},
+		{Hash: "b", Tags: []string{"!dropped", "am:ep:S01E01"}, Progress: 1},
+	}}
+	show := animelist.Entry{Titles: []string{"Show"}, ListStatus: animelist.ListStatusWatching}
+	dropped := animelist.Entry{Titles: []string{"Dropped"}, ListStatus: animelist.ListStatusDropped}
+
+	c := New(Dependencies{
+		TorrentClient: torrentClient,
+		Blocklist:     failingBlocklist{},
+		Config: Config{
+			StalledTimeout:     time.Hour,
+			RemoveListStatuses: []animelist.ListStatus{animelist.ListStatusDropped},
+			RemoveKeepFiles:    true,
+		},
+	})
+
+	// Failing to watch stalled torrents doesn't stop the next passes.
+	require.NoError(t, c.manageTorrents(t.Context(), []animelist.Entry{show}, []animelist.Entry{dropped}))
+	require.Equal(t, []string{"b"}, torrentClient.deleted)
+}
+
+func Test_manageTorrents_syncProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "failing sync continues", err: errors.New("list failed")},
+		{name: "unavailable torrent client stops", err: torrentclient.ErrUnavailable, wantErr: torrentclient.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Dependencies{
+				TorrentClient:   failingTorrentClient{fakeTorrentClient: &fakeTorrentClient{}, err: tt.err},
+				AnimeListWriter: fakeAnimeListWriter{},
+				Config:          Config{SyncProgress: true},
+			})
+
+			entries := []animelist.Entry{{Titles: []string{"Show"}}}
+			require.ErrorIs(t, c.manageTorrents(t.Context(), entries, nil), tt.wantErr)
+		})
+	}
+}
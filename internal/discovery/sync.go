@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/parser"
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// completedProgress returns the last episode of the given season completely downloaded, without gaps from the first episode.
+// Season batches count as the whole season, using numEpisodes.
+func completedProgress(torrents []torrentclient.Torrent, mapping tags.EpisodeMapping, season, numEpisodes int) int {
+	var completed []tags.Tag
+
+	for _, torrent := range torrents {
+		if torrent.Progress < 1 {
+			continue
+		}
+
+		tag := mapping.ToSeasonal(torrentTag(torrent))
+		if tag.IsZero() || tag.FirstSeason() > season || tag.LastSeason() < season {
+			continue
+		}
+
+		if len(tag.Episodes) == 0 || tag.LastSeason() > season {
+			tag = tags.Tag{Seasons: []int{season}}
+		}
+
+		completed = append(completed, tag)
+	}
+
+	set := tags.NewEpisodeSet(completed...)
+	if set.IsWhole(season) {
+		return numEpisodes
+	}
+
+	ranges := set.Ranges(season)
+	if len(ranges) == 0 || ranges[0].First > 1 {
+		return 0
+	}
+
+	return int(ranges[0].Last)
+}
+
+// syncStatus returns the list status an entry should have after progressing.
+func syncStatus(entry animelist.Entry, progress int) animelist.ListStatus {
+	if entry.NumEpisodes > 0 && progress >= entry.NumEpisodes && entry.AiringStatus == animelist.AiringStatusAired {
+		return animelist.ListStatusCompleted
+	}
+
+	if entry.ListStatus == animelist.ListStatusPlanToWatch {
+		return animelist.ListStatusWatching
+	}
+
+	return entry.ListStatus
+}
+
+// SyncProgress updates the anime list progress of each entry, based on the completed torrents from the torrent client.
+// Progress is never decreased, so it's safe to run it multiple times.
+func (c *Controller) SyncProgress(ctx context.Context, entries []animelist.Entry) error {
+	for _, entry := range entries {
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		progress := completedProgress(torrents, episodeMapping(entry), parser.EntrySeason(entry), entry.NumEpisodes)
+		if entry.NumEpisodes > 0 {
+			progress = min(progress, entry.NumEpisodes)
+		}
+
+		if progress <= entry.Progress {
+			continue
+		}
+
+		status := syncStatus(entry, progress)
+
+		err = c.dep.AnimeListWriter.UpdateProgress(ctx, entry, progress, status)
+		switch {
+		case errors.Is(err, context.Canceled):
+			return err
+		case err != nil:
+			logger.
+				Error().
+				Err(err).
+				Msg("failed to sync anime list progress")
+			continue
+		}
+
+		logger.
+			Info().
+			Int("progress", progress).
+			Stringer("status", status).
+			Msg("synced anime list progress")
+	}
+
+	return nil
+}
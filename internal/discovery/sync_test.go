@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_completedProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		torrents    []torrentclient.Torrent
+		season      int
+		numEpisodes int
+		want        int
+	}{
+		{
+			name: "empty",
+			want: 0,
+		},
+		{
+			name: "ignores incomplete torrents",
+			torrents: []torrentclient.Torrent{
+				{Tags: []string{"!show", "S1E1"}, Progress: 1},
+				{Tags: []string{"!show", "S1E2"}, Progress: 0.5},
+			},
+			season: 1,
+			want:   1,
+		},
+		{
+			name: "ignores other seasons",
+			torrents: []torrentclient.Torrent{
+				{Tags: []string{"!show", "S1E12"}, Progress: 1},
+				{Tags: []string{"!show", "S2E1"}, Progress: 1},
+				{Tags: []string{"!show", "S2E2-3"}, Progress: 1},
+			},
+			season: 2,
+			want:   3,
+		},
+		{
+			name: "stops at the first gap",
+			torrents: []torrentclient.Torrent{
+				{Tags: []string{"!show", "S1E1"}, Progress: 1},
+				{Tags: []string{"!show", "S1E2"}, Progress: 1},
+				{Tags: []string{"!show", "S1E5"}, Progress: 1},
+			},
+			season: 1,
+			want:   2,
+		},
+		{
+			name: "missing first episode",
+			torrents: []torrentclient.Torrent{
+				{Tags: []string{"!show", "S1E2"}, Progress: 1},
+				{Tags: []string{"!show", "S1E3"}, Progress: 1},
+			},
+			season: 1,
+			want:   0,
+		},
+		{
+			name: "season batch",
+			torrents: []torrentclient.Torrent{
+				{Tags: []string{"!show", "am:ep:S02"}, Progress: 1},
+			},
+			season:      2,
+			numEpisodes: 12,
+			want:        12,
+		},
+		{
+			name: "no tags",
+			torrents: []torrentclient.Torrent{
+				{Progress: 1},
+			},
+			season: 1,
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := completedProgress(tt.torrents, tags.EpisodeMapping{}, tt.season, tt.numEpisodes)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_syncStatus(t *testing.T) {
+	t.Run("plan to watch becomes watching", func(t *testing.T) {
+		entry := animelist.Entry{ListStatus: animelist.ListStatusPlanToWatch, NumEpisodes: 12}
+		require.Equal(t, animelist.ListStatusWatching, syncStatus(entry, 1))
+	})
+
+	t.Run("last episode of aired show completes", func(t *testing.T) {
+		entry := animelist.Entry{
+			ListStatus:   animelist.ListStatusWatching,
+			AiringStatus: animelist.AiringStatusAired,
+			NumEpisodes:  12,
+		}
+		require.Equal(t, animelist.ListStatusCompleted, syncStatus(entry, 12))
+	})
+
+	t.Run("airing show keeps status", func(t *testing.T) {
+		entry := animelist.Entry{
+			ListStatus:   animelist.ListStatusWatching,
+			AiringStatus: animelist.AiringStatusAiring,
+			NumEpisodes:  12,
+		}
+		require.Equal(t, animelist.ListStatusWatching, syncStatus(entry, 12))
+	})
+}
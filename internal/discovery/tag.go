@@ -2,7 +2,9 @@ package discovery
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"slices"
 	"strconv"
 
 	"github.com/sonalys/animeman/internal/parser"
@@ -89,8 +91,37 @@ func tagCompare(a, b tags.Tag) int {
 	return 0
 }
 
+// torrentTag decodes the season episode tag of a torrent. Movies have a zero tag.
+func torrentTag(torrent torrentclient.Torrent) tags.Tag {
+	return tags.Decode(torrent.Tags).Episode
+}
+
+// migrateLegacyTags returns the namespaced tags replacing the legacy season episode tags, and the legacy tags to remove.
+// Example: [!show, S1E5v2] adds [am:ep:S01E05, am:v:2] and removes [S1E5v2].
+func migrateLegacyTags(torrentTags []string) (add, remove []string) {
+	remove = tags.LegacyTags(torrentTags)
+	if len(remove) == 0 {
+		return nil, nil
+	}
+
+	record := tags.Decode(torrentTags)
+	for _, tag := range tags.Encode(tags.Record{Episode: record.Episode, Version: record.Version}) {
+		if !slices.Contains(torrentTags, tag) {
+			add = append(add, tag)
+		}
+	}
+
+	return add, remove
+}
+
+// isSpecialTag returns true for season zero tags, used by specials.
+func isSpecialTag(tag tags.Tag) bool {
+	return len(tag.Seasons) > 0 && tag.LastSeason() == 0
+}
+
 // getLatestTag is a pure function implementation for fetching the latest tag from a list of torrent entries.
-func getLatestTag(torrents []torrentclient.Torrent) tags.Tag {
+// Tags with absolute episode numbers are converted into season episode numbers using the mapping.
+func getLatestTag(torrents []torrentclient.Torrent, mapping tags.EpisodeMapping) tags.Tag {
 	if len(torrents) == 0 {
 		return tags.Tag{}
 	}
@@ -98,10 +129,7 @@ func getLatestTag(torrents []torrentclient.Torrent) tags.Tag {
 	var latestTag tags.Tag
 
 	for _, torrent := range torrents {
-		tags := torrent.Tags
-		seasonEpisodeTag := tags[len(tags)-1]
-		meta := parser.Parse(seasonEpisodeTag, 1)
-		tag := meta.Tag
+		tag := mapping.ToSeasonal(torrentTag(torrent))
 
 		if latestTag.IsZero() || tagCompare(tag, latestTag) > 0 {
 			latestTag = tag
@@ -110,3 +138,184 @@ func getLatestTag(torrents []torrentclient.Torrent) tags.Tag {
 
 	return latestTag
 }
+
+type (
+	// downloadedEpisode holds the highest release version downloaded for an episode, and all it's torrents.
+	downloadedEpisode struct {
+		Tag     tags.Tag
+		Version int
+		Hashes  []string
+		// Completed is true when any of it's torrents finished downloading.
+		Completed bool
+		// Superseded holds the torrents with a lower version than a completely downloaded one.
+		Superseded []string
+		// Owned is true for episodes imported from disk, without torrents.
+		Owned bool
+		// Replacement is true for batches downloaded for replacing single episodes.
+		Replacement bool
+	}
+
+	// downloadedEpisodes maps season episode tags, like S1E5, to their downloaded releases.
+	downloadedEpisodes map[string]downloadedEpisode
+)
+
+// releaseVersion returns the release version, considering unspecified versions as the first release.
+func releaseVersion(version int) int {
+	return max(version, 1)
+}
+
+// newDownloadedEpisodes indexes the torrents by their season episode tag.
+// Tags with absolute episode numbers are converted into season episode numbers using the mapping.
+// Torrents are only superseded by completely downloaded versions, so an episode is never left without a playable release.
+func newDownloadedEpisodes(torrents []torrentclient.Torrent, mapping tags.EpisodeMapping) downloadedEpisodes {
+	out := make(downloadedEpisodes, len(torrents))
+	completedVersions := make(map[string]int)
+
+	for _, torrent := range torrents {
+		record := tags.Decode(torrent.Tags)
+		tag := mapping.ToSeasonal(record.Episode)
+		key := tag.String()
+
+		downloaded := out[key]
+		downloaded.Tag = tag
+		downloaded.Version = max(downloaded.Version, releaseVersion(record.Version))
+		downloaded.Hashes = append(downloaded.Hashes, torrent.Hash)
+		downloaded.Completed = downloaded.Completed || torrent.Progress >= 1
+		downloaded.Replacement = downloaded.Replacement || record.Replacement
+		out[key] = downloaded
+
+		if torrent.Progress >= 1 {
+			completedVersions[key] = max(completedVersions[key], releaseVersion(record.Version))
+		}
+	}
+
+	for _, torrent := range torrents {
+		record := tags.Decode(torrent.Tags)
+		key := mapping.ToSeasonal(record.Episode).String()
+
+		if releaseVersion(record.Version) < completedVersions[key] {
+			downloaded := out[key]
+			downloaded.Superseded = append(downloaded.Superseded, torrent.Hash)
+			out[key] = downloaded
+		}
+	}
+
+	return out
+}
+
+// addOwned indexes the episodes imported from disk, unless they were also downloaded.
+// Owned episodes are never upgraded, since their release version is unknown.
+func (d downloadedEpisodes) addOwned(owned []tags.Tag) {
+	for _, tag := range owned {
+		if _, ok := d[tag.String()]; ok {
+			continue
+		}
+
+		d[tag.String()] = downloadedEpisode{
+			Tag:       tag,
+			Version:   releaseVersion(0),
+			Completed: true,
+			Owned:     true,
+		}
+	}
+}
+
+// episodeSet returns all downloaded episodes.
+func (d downloadedEpisodes) episodeSet() tags.EpisodeSet {
+	downloadedTags := make([]tags.Tag, 0, len(d))
+	for _, downloaded := range d {
+		downloadedTags = append(downloadedTags, downloaded.Tag)
+	}
+	return tags.NewEpisodeSet(downloadedTags...)
+}
+
+// episodeCoverage returns the episodes considered already downloaded, so they are not searched again.
+// Episodes before the first downloaded one of a season are considered covered, since tracking may start mid season.
+// Seasons before the last downloaded season are considered covered, unless they have episodes, so their gaps are filled.
+// Example: S1E5-6, S1E8 and S3E2 covers S1E1-6, S1E8, S2 and S3E1-2, missing S1E7.
+func episodeCoverage(downloaded tags.EpisodeSet) tags.EpisodeSet {
+	seasons := downloaded.Seasons()
+	if len(seasons) == 0 {
+		return downloaded
+	}
+
+	covered := make([]tags.Tag, 0, len(seasons))
+
+	for season := 1; season < seasons[len(seasons)-1]; season++ {
+		if !slices.Contains(seasons, season) {
+			covered = append(covered, tags.Tag{Seasons: []int{season}})
+		}
+	}
+
+	for _, season := range seasons {
+		ranges := downloaded.Ranges(season)
+		if downloaded.IsWhole(season) || len(ranges) == 0 {
+			continue
+		}
+
+		first := ranges[0].First
+		for _, r := range ranges {
+			first = min(first, r.First)
+		}
+
+		if before := math.Ceil(first) - 1; before >= 1 {
+			covered = append(covered, tags.Tag{Seasons: []int{season}, Episodes: []float64{1, before}})
+		}
+	}
+
+	return downloaded.Union(tags.NewEpisodeSet(covered...))
+}
+
+// isUpgrade returns true when the release is a higher version of an already downloaded episode.
+func (d downloadedEpisodes) isUpgrade(release parser.ParsedNyaa) bool {
+	downloaded, ok := d[release.ExtractedMetadata.Tag.String()]
+	return ok && !downloaded.Owned && releaseVersion(release.ExtractedMetadata.Version) > downloaded.Version
+}
+
+// superseded returns the torrents replaced by completely downloaded upgrades.
+// Upgrades still downloading keep the previous torrents, until a later run.
+func (d downloadedEpisodes) superseded() []string {
+	var hashes []string
+
+	for _, downloaded := range d {
+		hashes = append(hashes, downloaded.Superseded...)
+	}
+
+	slices.Sort(hashes)
+
+	return hashes
+}
+
+// replacedEpisodes returns the single episode torrents contained by completely downloaded replacement batches.
+// Other batches, like partial batches filling gaps, keep the single episodes.
+func (d downloadedEpisodes) replacedEpisodes() []string {
+	var completed []tags.Tag
+
+	for _, downloaded := range d {
+		if downloaded.Completed && downloaded.Replacement && downloaded.Tag.IsMultiEpisode() {
+			completed = append(completed, downloaded.Tag)
+		}
+	}
+
+	if len(completed) == 0 {
+		return nil
+	}
+
+	batches := tags.NewEpisodeSet(completed...)
+
+	var hashes []string
+	for _, downloaded := range d {
+		if !downloaded.Tag.IsMultiEpisode() && batches.Contains(downloaded.Tag) {
+			hashes = append(hashes, downloaded.Hashes...)
+		}
+	}
+
+	slices.Sort(hashes)
+
+	return hashes
+}
+
+// sharesSeason returns true when both tags have a season in common.
+func sharesSeason(a, b tags.Tag) bool {
+	return a.FirstSeason() <= b.LastSeason() && b.FirstSeason() <= a.LastSeason()
+}
@@ -3,6 +3,7 @@ package discovery
 import (
 	"testing"
 
+	"github.com/sonalys/animeman/internal/parser"
 	"github.com/sonalys/animeman/internal/tags"
 	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 	"github.com/stretchr/testify/require"
@@ -21,7 +22,7 @@ func Test_getLatestTag(t *testing.T) {
 			name: "batch and season",
 			args: args{
 				torrents: []torrentclient.Torrent{
-					{Tags: []string{"S1E1~13"}},
+					{Tags: []string{"am:ep:S01E01-13"}},
 					{Tags: []string{"S1E2"}},
 					{Tags: []string{"S1E3"}},
 				},
@@ -35,8 +36,8 @@ func Test_getLatestTag(t *testing.T) {
 			name: "same season half episode",
 			args: args{
 				torrents: []torrentclient.Torrent{
-					{Tags: []string{"ore dake level up na ken S1E7"}},
-					{Tags: []string{"solo leveling S1E7.5"}},
+					{Tags: []string{"!ore dake level up na ken", "am:ep:S01E07"}},
+					{Tags: []string{"!solo leveling", "am:ep:S01E7.5"}},
 				},
 			},
 			want: tags.Tag{
@@ -48,7 +49,7 @@ func Test_getLatestTag(t *testing.T) {
 			name: "batch and same season",
 			args: args{
 				torrents: []torrentclient.Torrent{
-					{Tags: []string{"S3"}},
+					{Tags: []string{"am:ep:S03"}},
 					{Tags: []string{"S3E2"}},
 				},
 			},
@@ -64,7 +65,7 @@ func Test_getLatestTag(t *testing.T) {
 			name: "one tag",
 			args: args{
 				torrents: []torrentclient.Torrent{
-					{Tags: []string{"S01"}},
+					{Tags: []string{"am:ep:S01"}},
 				},
 			},
 			want: tags.Tag{
@@ -103,7 +104,7 @@ func Test_getLatestTag(t *testing.T) {
 			name: "batch and season",
 			args: args{
 				torrents: []torrentclient.Torrent{
-					{Tags: []string{"S3"}},
+					{Tags: []string{"am:ep:S03"}},
 					{Tags: []string{"S2E2"}},
 					{Tags: []string{"S1E3"}},
 				},
@@ -112,12 +113,32 @@ func Test_getLatestTag(t *testing.T) {
 				Seasons: []int{3},
 			},
 		},
+		{
+			name: "namespaced tags with user tags",
+			args: args{
+				torrents: []torrentclient.Torrent{
+					{Tags: []string{"!show", "am:ep:S01E04", "watched"}},
+					{Tags: []string{"!show", "am:ep:S01E05", "am:v:2", "zzz"}},
+				},
+			},
+			want: tags.SeasonEpisode(1, 5),
+		},
+		{
+			name: "torrent without tags",
+			args: args{
+				torrents: []torrentclient.Torrent{
+					{Tags: []string{"S1E2"}},
+					{Tags: nil},
+				},
+			},
+			want: tags.SeasonEpisode(1, 2),
+		},
 		{
 			name: "batches of different seasons",
 			args: args{
 				torrents: []torrentclient.Torrent{
-					{Tags: []string{"S3"}},
-					{Tags: []string{"S4"}},
+					{Tags: []string{"am:ep:S03"}},
+					{Tags: []string{"am:ep:S04"}},
 				},
 			},
 			want: tags.Tag{
@@ -127,7 +148,7 @@ func Test_getLatestTag(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getLatestTag(tt.args.torrents); tagCompare(got, tt.want) != 0 {
+			if got := getLatestTag(tt.args.torrents, tags.EpisodeMapping{}); tagCompare(got, tt.want) != 0 {
 				t.Errorf("getLatestTag() = %v, want %v", got, tt.want)
 			}
 		})
@@ -222,3 +243,188 @@ func Test_tagCompare(t *testing.T) {
 		require.Equal(t, tagCompare(tagA, tags.Zero), 1)
 	})
 }
+
+func Test_newDownloadedEpisodes(t *testing.T) {
+	torrents := []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "S1E5"}},
+		{Hash: "b", Tags: []string{"!show", "S1E5v2"}},
+		{Hash: "c", Tags: []string{"!show", "S1E15"}},
+	}
+
+	got := newDownloadedEpisodes(torrents, tags.EpisodeMapping{Seasons: []int{12, 12}})
+	require.Equal(t, downloadedEpisodes{
+		"S1E5": {Tag: tags.SeasonEpisode(1, 5), Version: 2, Hashes: []string{"a", "b"}},
+		"S2E3": {Tag: tags.SeasonEpisode(2, 3), Version: 1, Hashes: []string{"c"}},
+	}, got)
+
+	torrents = []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}},
+		{Hash: "b", Tags: []string{"!show", "am:ep:S01E05", "am:v:3", "seeding"}},
+	}
+
+	got = newDownloadedEpisodes(torrents, tags.EpisodeMapping{})
+	require.Equal(t, downloadedEpisodes{
+		"S1E5": {Tag: tags.SeasonEpisode(1, 5), Version: 3, Hashes: []string{"a", "b"}},
+	}, got)
+	require.Equal(t, "S1E5", got.episodeSet().String())
+}
+
+func Test_addOwned(t *testing.T) {
+	torrents := []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}},
+	}
+
+	got := newDownloadedEpisodes(torrents, tags.EpisodeMapping{})
+	got.addOwned([]tags.Tag{tags.SeasonEpisode(1, 4), tags.SeasonEpisode(1, 5)})
+	require.Equal(t, downloadedEpisodes{
+		"S1E4": {Tag: tags.SeasonEpisode(1, 4), Version: 1, Completed: true, Owned: true},
+		"S1E5": {Tag: tags.SeasonEpisode(1, 5), Version: 1, Hashes: []string{"a"}},
+	}, got)
+
+	// Owned episodes are never upgraded, their release version is unknown.
+	release := func(episode float64) parser.ParsedNyaa {
+		return parser.ParsedNyaa{ExtractedMetadata: parser.Metadata{Tag: tags.SeasonEpisode(1, episode), Version: 2}}
+	}
+	require.False(t, got.isUpgrade(release(4)))
+	require.True(t, got.isUpgrade(release(5)))
+}
+
+func Test_superseded(t *testing.T) {
+	t.Run("upgrade downloading", func(t *testing.T) {
+		torrents := []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1},
+			{Hash: "b", Tags: []string{"!show", "am:ep:S01E05", "am:v:2"}, Progress: 0.5},
+		}
+
+		got := newDownloadedEpisodes(torrents, tags.EpisodeMapping{})
+		require.Empty(t, got.superseded())
+	})
+
+	t.Run("upgrade completed", func(t *testing.T) {
+		torrents := []torrentclient.Torrent{
+			{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1},
+			{Hash: "b", Tags: []string{"!show", "am:ep:S01E05", "am:v:2"}, Progress: 1},
+			{Hash: "c", Tags: []string{"!show", "am:ep:S01E05", "am:v:3"}, Progress: 0.5},
+			{Hash: "d", Tags: []string{"!show", "am:ep:S01E06"}, Progress: 1},
+		}
+
+		got := newDownloadedEpisodes(torrents, tags.EpisodeMapping{})
+		require.Equal(t, []string{"a"}, got.superseded())
+	})
+}
+
+func Test_replacedEpisodes(t *testing.T) {
+	torrents := []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E05"}, Progress: 1},
+		{Hash: "b", Tags: []string{"!show", "am:ep:S01E06"}},
+		{Hash: "c", Tags: []string{"!show", "am:ep:S01E13"}, Progress: 1},
+		{Hash: "d", Tags: []string{"!show", "am:ep:S02E01"}, Progress: 1},
+		{Hash: "e", Tags: []string{"!show", "am:ep:S01E01-12", "am:replacement"}, Progress: 1},
+		{Hash: "f", Tags: []string{"!show", "am:ep:S02", "am:replacement"}, Progress: 0.5},
+	}
+
+	got := newDownloadedEpisodes(torrents, tags.EpisodeMapping{})
+	require.Equal(t, []string{"a", "b"}, got.replacedEpisodes())
+
+	got = newDownloadedEpisodes(torrents[:4], tags.EpisodeMapping{})
+	require.Empty(t, got.replacedEpisodes())
+
+	t.Run("batches filling gaps keep single episodes", func(t *testing.T) {
+		torrents := []torrentclient.Torrent{
+			{Hash: "e1", Tags: []string{"!show", "am:ep:S01E01"}, Progress: 1},
+			{Hash: "e8", Tags: []string{"!show", "am:ep:S01E08"}, Progress: 1},
+			{Hash: "gap", Tags: []string{"!show", "am:ep:S01E01-07"}, Progress: 1},
+		}
+
+		got := newDownloadedEpisodes(torrents, tags.EpisodeMapping{})
+		require.Empty(t, got.replacedEpisodes())
+	})
+}
+
+func Test_episodeCoverage(t *testing.T) {
+	tests := []struct {
+		name       string
+		downloaded []tags.Tag
+		want       string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:       "mid season",
+			downloaded: []tags.Tag{tags.SeasonEpisode(1, 5), tags.SeasonEpisode(1, 6), tags.SeasonEpisode(1, 8)},
+			want:       "S1E1-6,8",
+		},
+		{
+			name:       "previous seasons",
+			downloaded: []tags.Tag{tags.SeasonEpisode(1, 5), tags.SeasonEpisode(3, 2)},
+			want:       "S1E1-5 S2 S3E1-2",
+		},
+		{
+			name:       "half episode",
+			downloaded: []tags.Tag{{Seasons: []int{1}, Episodes: []float64{7.5}}},
+			want:       "S1E1-7,7.5",
+		},
+		{
+			name:       "whole season",
+			downloaded: []tags.Tag{{Seasons: []int{2}}},
+			want:       "S1 S2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, episodeCoverage(tags.NewEpisodeSet(tt.downloaded...)).String())
+		})
+	}
+}
+
+func Test_migrateLegacyTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       []string
+		wantAdd    []string
+		wantRemove []string
+	}{
+		{
+			name:       "episode",
+			tags:       []string{"!show", "S1E5"},
+			wantAdd:    []string{"am:ep:S01E05"},
+			wantRemove: []string{"S1E5"},
+		},
+		{
+			name:       "version",
+			tags:       []string{"!show", "S1E5v2", "seen"},
+			wantAdd:    []string{"am:ep:S01E05", "am:v:2"},
+			wantRemove: []string{"S1E5v2"},
+		},
+		{
+			name: "already migrated",
+			tags: []string{"!show", "S1E5", "am:ep:S01E05"},
+		},
+		{
+			name:       "user season tag",
+			tags:       []string{"!show", "S1E5", "S2"},
+			wantAdd:    []string{"am:ep:S01E05"},
+			wantRemove: []string{"S1E5"},
+		},
+		{
+			name: "user tags without title",
+			tags: []string{"S2", "Favorites S1"},
+		},
+		{
+			name: "namespaced",
+			tags: []string{"!show", "am:ep:S01E05"},
+		},
+		{
+			name: "movie",
+			tags: []string{"!movie"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, remove := migrateLegacyTags(tt.tags)
+			require.Equal(t, tt.wantAdd, add)
+			require.Equal(t, tt.wantRemove, remove)
+		})
+	}
+}
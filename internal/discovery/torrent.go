@@ -3,6 +3,7 @@ package discovery
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"unicode"
@@ -15,16 +16,27 @@ import (
 	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 )
 
-// findLatestTag will receive an anime list entry and return all torrents listed from the anime.
-func (c *Controller) findLatestTag(ctx context.Context, entry animelist.Entry) (tags.Tag, error) {
+// listEntryTorrents will receive an anime list entry and return all torrents listed from the anime.
+// Torrents of the configured category are listed by the show tag of every identifier and by every title tag, ignoring torrents tagged with another show.
+// Show tags match by any identifier, since the entry key changes when new identifiers are known, like an AniList ID for a MAL entry.
+func (c *Controller) listEntryTorrents(ctx context.Context, entry animelist.Entry) ([]torrentclient.Torrent, error) {
 	logger := getLogger(ctx)
 	torrents := make([]torrentclient.Torrent, 0, 100)
 
-	for _, title := range entry.Titles {
+	searchTags := append(
+		utils.Map(entry.IDs.Keys(), tags.ShowTag),
+		utils.Map(entry.Titles, parser.BuildTitleTag)...,
+	)
+
+	for _, tag := range searchTags {
 		req := &torrentclient.ListTorrentConfig{
-			Tag: utils.Pointer(parser.BuildTitleTag(title)),
+			Category: &c.dep.Config.Category,
+			Tag:      utils.Pointer(tag),
 		}
 		resp, err := c.dep.TorrentClient.List(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing torrents: %w", err)
+		}
 
 		if len(resp) == 0 {
 			continue
@@ -35,14 +47,51 @@ func (c *Controller) findLatestTag(ctx context.Context, entry animelist.Entry) (
 			Str("tag", *req.Tag).
 			Msg("identified entry tag on torrent client")
 
-		if err != nil {
-			return tags.Tag{}, fmt.Errorf("listing torrents: %w", err)
+		for _, torrent := range resp {
+			if torrentShow := tags.Decode(torrent.Tags).Show; torrentShow != "" && !entry.IDs.IsZero() && !entry.IDs.HasKey(torrentShow) {
+				continue
+			}
+
+			if slices.ContainsFunc(torrents, func(t torrentclient.Torrent) bool { return t.Hash == torrent.Hash }) {
+				continue
+			}
+
+			torrents = append(torrents, torrent)
 		}
+	}
+
+	return torrents, nil
+}
+
+// findLatestTag will receive an anime list entry and return the latest tag from all it's torrents and owned episodes.
+// It also returns the downloaded episodes, for detecting release upgrades.
+func (c *Controller) findLatestTag(ctx context.Context, entry animelist.Entry) (tags.Tag, downloadedEpisodes, error) {
+	logger := getLogger(ctx)
+
+	torrents, err := c.listEntryTorrents(ctx, entry)
+	if err != nil {
+		return tags.Tag{}, nil, err
+	}
+
+	// Specials and the main seasons are tracked separately, even when sharing a title tag.
+	special := entry.Format == animelist.MediaFormatSpecial
+	torrents = utils.Filter(torrents, func(torrent torrentclient.Torrent) bool {
+		return isSpecialTag(torrentTag(torrent)) == special
+	})
+
+	owned := utils.Filter(c.ownedEpisodes(entry), func(tag tags.Tag) bool {
+		return isSpecialTag(tag) == special
+	})
 
-		torrents = append(torrents, resp...)
+	mapping := episodeMapping(entry)
+
+	latestTag := getLatestTag(torrents, mapping)
+	for _, tag := range owned {
+		if latestTag.IsZero() || tagCompare(tag, latestTag) > 0 {
+			latestTag = tag
+		}
 	}
 
-	latestTag := getLatestTag(torrents)
 	if !latestTag.IsZero() {
 		logger.
 			Debug().
@@ -50,7 +99,10 @@ func (c *Controller) findLatestTag(ctx context.Context, entry animelist.Entry) (
 			Msg("identified latest tag on torrent client")
 	}
 
-	return latestTag, nil
+	downloaded := newDownloadedEpisodes(torrents, mapping)
+	downloaded.addOwned(owned)
+
+	return latestTag, downloaded, nil
 }
 
 // TorrentGetDownloadPath returns a torrent path, creating a show folder if configured.
@@ -61,6 +113,14 @@ func (c *Controller) TorrentGetDownloadPath(title string) (path string) {
 	return c.dep.Config.DownloadPath
 }
 
+// TorrentGetCompletedPath returns the path completed torrents are moved into, keeping the show folder if configured.
+func (c *Controller) TorrentGetCompletedPath(title string) (path string) {
+	if c.dep.Config.CreateShowFolder {
+		return fmt.Sprintf("%s/%s", c.dep.Config.CompletedPath, title)
+	}
+	return c.dep.Config.CompletedPath
+}
+
 func (c *Controller) buildTorrentName(title string, parsedNyaa parser.ParsedNyaa) string {
 	var b strings.Builder
 
@@ -133,13 +193,23 @@ func (c *Controller) AddTorrentEntry(ctx context.Context, animeListEntry animeli
 	// Use nyaa metadata, but with anime list title.
 	// This behavior avoids different sources creating different tags and downloading the same episode twice.
 	meta.Title = selectedTitle
-	tags := meta.BuildTorrentTags()
+	torrentTags := meta.BuildTorrentTags()
+	if show := animeListEntry.IDs.Key(); show != "" {
+		torrentTags = append(torrentTags, tags.ShowTag(show))
+	}
+	if parsedNyaa.Replacement {
+		torrentTags = append(torrentTags, tags.ReplacementTag)
+	}
 
 	req := &torrentclient.AddTorrentConfig{
-		Tags:     tags,
-		URLs:     []string{parsedNyaa.NyaaTorrent.Link},
-		Category: c.dep.Config.Category,
-		SavePath: c.TorrentGetDownloadPath(selectedTitle),
+		Tags:                   torrentTags,
+		URLs:                   []string{parsedNyaa.NyaaTorrent.Link},
+		Category:               c.dep.Config.Category,
+		SavePath:               c.TorrentGetDownloadPath(selectedTitle),
+		TopOfQueue:             c.dep.Config.AddToTopOfQueue,
+		Sequential:             c.dep.Config.SequentialDownload,
+		FirstLastPiecePriority: c.dep.Config.FirstLastPiecePriority,
+		Limits:                 c.dep.Config.TransferLimits,
 	}
 
 	if c.dep.Config.RenameTorrent {
@@ -153,32 +223,138 @@ func (c *Controller) AddTorrentEntry(ctx context.Context, animeListEntry animeli
 	return nil
 }
 
+// ReplaceUpgraded removes the torrents of each entry superseded by completely downloaded upgrades, like 05 once 05v2 finished.
+// It runs on every cycle, since upgrades finish downloading after the search finding them, keeping the files if configured.
+func (c *Controller) ReplaceUpgraded(ctx context.Context, entries []animelist.Entry) error {
+	for _, entry := range entries {
+		if isSingleRelease(entry) {
+			continue
+		}
+
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		downloaded := newDownloadedEpisodes(torrents, episodeMapping(entry))
+		if err := c.removeTorrents(ctx, downloaded.superseded(), "replaced by an upgraded release"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveReplacedEpisodes removes the single episode torrents of each entry contained by completely downloaded replacement batches.
+// It runs on every cycle, since batches finish downloading after the search finding them, keeping the files if configured.
+func (c *Controller) RemoveReplacedEpisodes(ctx context.Context, entries []animelist.Entry) error {
+	for _, entry := range entries {
+		if isSingleRelease(entry) {
+			continue
+		}
+
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		downloaded := newDownloadedEpisodes(torrents, episodeMapping(entry))
+		if err := c.removeTorrents(ctx, downloaded.replacedEpisodes(), "replaced by a batch"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // TorrentRegenerateTags will scan all torrents from the configured category and update their tags.
 // This function exists for when you already have a collection of Anime categorized torrents.
 // This function will tag all entries from the configured category for smart episode detection and filtering.
+// Torrents with legacy season episode tags, like S1E5v2, are migrated to namespaced tags, like am:ep:S01E05 and am:v:2.
+// The show identity tag can't be detected from torrent names, so only new torrents have it.
 func (c *Controller) TorrentRegenerateTags(ctx context.Context) error {
 	torrents, err := c.dep.TorrentClient.List(ctx, &torrentclient.ListTorrentConfig{
 		Category: &c.dep.Config.Category,
-		Tag:      utils.Pointer(""),
 	})
 	if err != nil {
 		return fmt.Errorf("listing torrents: %w", err)
 	}
 
 	for _, torrent := range torrents {
-		meta := parser.Parse(torrent.Name, 1)
-		tags := meta.BuildTorrentTags()
+		torrentTags := utils.Filter(torrent.Tags, func(tag string) bool { return tag != "" })
+
+		if len(torrentTags) == 0 {
+			if err := c.tagTorrent(ctx, torrent); err != nil {
+				return err
+			}
+			continue
+		}
 
-		log.
-			Info().
-			Any("metadata", meta).
-			Strs("tags", tags).
-			Msgf("updating torrent tags")
+		if err := c.migrateTorrentTags(ctx, torrent.Hash, torrentTags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-		if err := c.dep.TorrentClient.AddTorrentTags(ctx, []string{torrent.Hash}, tags); err != nil {
-			return fmt.Errorf("updating tags: %w", err)
+// tagTorrent tags an untagged torrent, parsing it's name.
+func (c *Controller) tagTorrent(ctx context.Context, torrent torrentclient.Torrent) error {
+	meta := parser.Parse(torrent.Name, 1)
+	torrentTags := meta.BuildTorrentTags()
+
+	log.
+		Info().
+		Any("metadata", meta).
+		Strs("tags", torrentTags).
+		Msgf("updating torrent tags")
+
+	if err := c.dep.TorrentClient.AddTorrentTags(ctx, []string{torrent.Hash}, torrentTags); err != nil {
+		return fmt.Errorf("updating tags: %w", err)
+	}
+
+	return nil
+}
+
+// migrateTorrentTags replaces the legacy season episode tags of a torrent with namespaced ones.
+func (c *Controller) migrateTorrentTags(ctx context.Context, hash string, torrentTags []string) error {
+	add, remove := migrateLegacyTags(torrentTags)
+	if len(remove) == 0 {
+		return nil
+	}
+
+	logger := getLogger(ctx)
+
+	if len(add) > 0 {
+		if err := c.dep.TorrentClient.AddTorrentTags(ctx, []string{hash}, add); err != nil {
+			return fmt.Errorf("adding namespaced tags: %w", err)
 		}
 	}
 
+	if err := c.dep.TorrentClient.RemoveTorrentTags(ctx, []string{hash}, remove); err != nil {
+		return fmt.Errorf("removing legacy tags: %w", err)
+	}
+
+	logger.
+		Info().
+		Str("hash", hash).
+		Strs("added", add).
+		Strs("removed", remove).
+		Msg("migrated legacy torrent tags")
+
 	return nil
 }
@@ -0,0 +1,97 @@
+package discovery
+
+import (
+	"context"
+	"slices"
+
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// torrentCache lists the torrents of the configured category once per discovery cycle, grouping them by tag in memory.
+// Every pass of a cycle lists the torrents of each show, so the torrent client is only listed again after changes.
+// Listings without the category, or of other categories, are not cached.
+type torrentCache struct {
+	TorrentClient
+	category string
+
+	listed   bool
+	torrents []torrentclient.Torrent
+	byTag    map[string][]torrentclient.Torrent
+}
+
+func newTorrentCache(client TorrentClient, category string) *torrentCache {
+	return &torrentCache{TorrentClient: client, category: category}
+}
+
+// reset discards the listed torrents, so changes made outside animeman are seen on the next cycle.
+func (c *torrentCache) reset() {
+	c.listed = false
+	c.torrents = nil
+	c.byTag = nil
+}
+
+func (c *torrentCache) List(ctx context.Context, arg *torrentclient.ListTorrentConfig) ([]torrentclient.Torrent, error) {
+	if arg.Category == nil || *arg.Category != c.category {
+		return c.TorrentClient.List(ctx, arg)
+	}
+
+	if !c.listed {
+		torrents, err := c.TorrentClient.List(ctx, &torrentclient.ListTorrentConfig{Category: &c.category})
+		if err != nil {
+			return nil, err
+		}
+
+		c.byTag = make(map[string][]torrentclient.Torrent)
+		for _, torrent := range torrents {
+			if len(torrent.Tags) == 0 {
+				c.byTag[""] = append(c.byTag[""], torrent)
+			}
+			for _, tag := range torrent.Tags {
+				c.byTag[tag] = append(c.byTag[tag], torrent)
+			}
+		}
+		c.torrents = torrents
+		c.listed = true
+	}
+
+	if arg.Tag == nil {
+		return slices.Clone(c.torrents), nil
+	}
+
+	return slices.Clone(c.byTag[*arg.Tag]), nil
+}
+
+func (c *torrentCache) AddTorrent(ctx context.Context, arg *torrentclient.AddTorrentConfig) error {
+	defer c.reset()
+	return c.TorrentClient.AddTorrent(ctx, arg)
+}
+
+func (c *torrentCache) AddTorrentTags(ctx context.Context, hashes []string, tags []string) error {
+	defer c.reset()
+	return c.TorrentClient.AddTorrentTags(ctx, hashes, tags)
+}
+
+func (c *torrentCache) RemoveTorrentTags(ctx context.Context, hashes []string, tags []string) error {
+	defer c.reset()
+	return c.TorrentClient.RemoveTorrentTags(ctx, hashes, tags)
+}
+
+func (c *torrentCache) DeleteTorrents(ctx context.Context, hashes []string, deleteFiles bool) error {
+	defer c.reset()
+	return c.TorrentClient.DeleteTorrents(ctx, hashes, deleteFiles)
+}
+
+func (c *torrentCache) SetShareLimits(ctx context.Context, hashes []string, limits torrentclient.ShareLimits) error {
+	defer c.reset()
+	return c.TorrentClient.SetShareLimits(ctx, hashes, limits)
+}
+
+func (c *torrentCache) SetLocation(ctx context.Context, hashes []string, location string) error {
+	defer c.reset()
+	return c.TorrentClient.SetLocation(ctx, hashes, location)
+}
+
+func (c *torrentCache) SetTransferLimits(ctx context.Context, hashes []string, limits torrentclient.TransferLimits) error {
+	defer c.reset()
+	return c.TorrentClient.SetTransferLimits(ctx, hashes, limits)
+}
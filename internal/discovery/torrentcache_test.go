@@ -0,0 +1,45 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_torrentCache(t *testing.T) {
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Category: "anime", Tags: []string{"!show", "am:ep:S01E01"}},
+		{Hash: "b", Category: "anime", Tags: []string{"!other", "am:ep:S01E01"}},
+		{Hash: "c", Category: "movies", Tags: []string{"!show"}},
+	}}
+	c := New(Dependencies{TorrentClient: torrentClient, Config: Config{Category: "anime"}})
+	show := animelist.Entry{Titles: []string{"Show"}}
+	other := animelist.Entry{Titles: []string{"Other"}}
+
+	got, err := c.listEntryTorrents(t.Context(), show)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, utils.Map(got, func(t torrentclient.Torrent) string { return t.Hash }))
+
+	got, err = c.listEntryTorrents(t.Context(), other)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, utils.Map(got, func(t torrentclient.Torrent) string { return t.Hash }))
+	require.Equal(t, 1, torrentClient.lists)
+
+	t.Run("changes list the torrents again", func(t *testing.T) {
+		require.NoError(t, c.dep.TorrentClient.DeleteTorrents(t.Context(), []string{"a"}, false))
+
+		got, err := c.listEntryTorrents(t.Context(), show)
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.Equal(t, 2, torrentClient.lists)
+	})
+
+	t.Run("other categories are not cached", func(t *testing.T) {
+		_, err := c.dep.TorrentClient.List(t.Context(), &torrentclient.ListTorrentConfig{Category: utils.Pointer("movies")})
+		require.NoError(t, err)
+		require.Equal(t, 3, torrentClient.lists)
+	})
+}
@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// stalledFor returns how long a downloading torrent has been stalled, or zero when it isn't stalled.
+// Torrents which never transferred data are stalled since they were added.
+func stalledFor(torrent torrentclient.Torrent, now time.Time) time.Duration {
+	if torrent.State != torrentclient.StateStalled || torrent.Progress >= 1 {
+		return 0
+	}
+
+	since := torrent.AddedOn
+	if torrent.LastActivity.After(since) {
+		since = torrent.LastActivity
+	}
+
+	if since.IsZero() {
+		return 0
+	}
+
+	return now.Sub(since)
+}
+
+// WatchStalled removes the torrents of each entry stalled for longer than the configured timeout, blocklisting their releases.
+// Entries with removed torrents are scanned right away, so the next best release of the same episodes is downloaded.
+func (c *Controller) WatchStalled(ctx context.Context, entries []animelist.Entry) error {
+	now := time.Now()
+
+	for _, entry := range entries {
+		logger := log.Logger.
+			With().
+			Str("title", selectIdealTitle(entry.Titles)).
+			Logger()
+
+		ctx := logger.WithContext(ctx)
+
+		torrents, err := c.listEntryTorrents(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("listing entry torrents: %w", err)
+		}
+
+		stalled := utils.Filter(torrents, func(torrent torrentclient.Torrent) bool {
+			return stalledFor(torrent, now) > c.dep.Config.StalledTimeout
+		})
+
+		if len(stalled) == 0 {
+			continue
+		}
+
+		hashes := utils.Map(stalled, func(torrent torrentclient.Torrent) string { return torrent.Hash })
+
+		if c.dep.Blocklist != nil {
+			for _, torrent := range stalled {
+				reason := fmt.Sprintf("%s stalled for %s", torrent.Name, stalledFor(torrent, now).Round(time.Minute))
+				if err := c.dep.Blocklist.Block(torrent.Hash, reason); err != nil {
+					return fmt.Errorf("blocking stalled release: %w", err)
+				}
+			}
+		}
+
+		if err := c.dep.TorrentClient.DeleteTorrents(ctx, hashes, true); err != nil {
+			return fmt.Errorf("deleting stalled torrents: %w", err)
+		}
+
+		c.intervalTracker.Reset(entry)
+
+		logger.
+			Info().
+			Strs("hashes", hashes).
+			Msg("removed stalled torrents, searching for another release")
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_stalledFor(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		torrent torrentclient.Torrent
+		want    time.Duration
+	}{
+		{
+			name:    "downloading",
+			torrent: torrentclient.Torrent{State: torrentclient.StateDownloading, AddedOn: now.Add(-time.Hour)},
+		},
+		{
+			name:    "never active",
+			torrent: torrentclient.Torrent{State: torrentclient.StateStalled, AddedOn: now.Add(-time.Hour)},
+			want:    time.Hour,
+		},
+		{
+			name: "last activity",
+			torrent: torrentclient.Torrent{
+				State:        torrentclient.StateStalled,
+				AddedOn:      now.Add(-time.Hour),
+				LastActivity: now.Add(-time.Minute),
+			},
+			want: time.Minute,
+		},
+		{
+			name:    "completed",
+			torrent: torrentclient.Torrent{State: torrentclient.StateStalled, Progress: 1, AddedOn: now.Add(-time.Hour)},
+		},
+		{
+			name:    "unknown added date",
+			torrent: torrentclient.Torrent{State: torrentclient.StateStalled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, stalledFor(tt.torrent, now))
+		})
+	}
+}
+
+func Test_WatchStalled(t *testing.T) {
+	now := time.Now()
+	torrentClient := &fakeTorrentClient{torrents: []torrentclient.Torrent{
+		{Hash: "a", Tags: []string{"!show", "am:ep:S01E01"}, State: torrentclient.StateStalled, AddedOn: now.Add(-2 * time.Hour)},
+		{Hash: "b", Tags: []string{"!show", "am:ep:S01E02"}, State: torrentclient.StateStalled, AddedOn: now.Add(-time.Minute)},
+		{Hash: "c", Tags: []string{"!show", "am:ep:S01E03"}, State: torrentclient.StateDownloading, AddedOn: now.Add(-2 * time.Hour)},
+	}}
+	blocklist := fakeBlocklist{}
+	entry := animelist.Entry{Titles: []string{"Show"}}
+
+	c := New(Dependencies{
+		TorrentClient: torrentClient,
+		Blocklist:     blocklist,
+		Config:        Config{StalledTimeout: time.Hour, PollFrequency: time.Minute},
+	})
+	c.intervalTracker.UpdateState(entry, false)
+
+	require.NoError(t, c.WatchStalled(t.Context(), []animelist.Entry{entry}))
+	require.Equal(t, []string{"a"}, torrentClient.deleted)
+	require.True(t, blocklist.IsBlocked("a"))
+	require.False(t, blocklist.IsBlocked("b"))
+	require.True(t, c.intervalTracker.GetNextScanTime(entry).Before(time.Now().Add(time.Second)))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -18,11 +19,40 @@ type (
 	ListStatus   string
 	AiringStatus string
 	Title        string
+	MediaFormat  string
+	RelationType string
+
+	AiringSchedule struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Edges []struct {
+			Node struct {
+				Episode  int `json:"episode"`
+				AiringAt int `json:"airingAt"`
+			} `json:"node"`
+		} `json:"edges"`
+	}
+
+	Relations struct {
+		Edges []struct {
+			RelationType RelationType `json:"relationType"`
+			Node         struct {
+				ID       int         `json:"id"`
+				Format   MediaFormat `json:"format"`
+				Episodes int         `json:"episodes"`
+			} `json:"node"`
+		} `json:"edges"`
+	}
 
 	AnimeListEntry struct {
-		Status ListStatus `json:"status"`
-		Media  struct {
+		Status   ListStatus `json:"status"`
+		Progress int        `json:"progress"`
+		Media    struct {
+			ID           int `json:"id"`
+			IDMal        int `json:"idMal"`
 			Type         string
+			Format       MediaFormat  `json:"format"`
 			AiringStatus AiringStatus `json:"status"`
 			Episodes     int          `json:"episodes"`
 			StartDate    struct {
@@ -40,15 +70,12 @@ type (
 				English string `json:"english"`
 				Native  string `json:"native"`
 			} `json:"title"`
-			AiringSchedule struct {
-				Edges []struct {
-					Node struct {
-						Episode  int `json:"episode"`
-						AiringAt int `json:"airingAt"`
-					} `json:"node"`
-				} `json:"edges"`
-			} `json:"airingSchedule"`
+			AiringSchedule AiringSchedule `json:"airingSchedule"`
+			Relations      Relations      `json:"relations"`
 		} `json:"media"`
+
+		// season is the season detected from the prequel relations.
+		season seasonInfo
 	}
 
 	GraphqlQuery struct {
@@ -69,24 +96,43 @@ type (
 
 const (
 	ListStatusWatching  ListStatus = "CURRENT"
+	ListStatusRepeating ListStatus = "REPEATING"
 	ListStatusCompleted ListStatus = "COMPLETED"
+	ListStatusPaused    ListStatus = "PAUSED"
 	ListStatusDropped   ListStatus = "DROPPED"
 	ListStatusPlanning  ListStatus = "PLANNING"
 )
 
 const (
-	AiringStatusAiring    AiringStatus = "AIRING"
-	AiringStatusCompleted AiringStatus = "COMPLETED"
-	AiringStatusFinished  AiringStatus = "FINISHED"
+	MediaFormatTV      MediaFormat = "TV"
+	MediaFormatTVShort MediaFormat = "TV_SHORT"
+	MediaFormatMovie   MediaFormat = "MOVIE"
+	MediaFormatSpecial MediaFormat = "SPECIAL"
+	MediaFormatOVA     MediaFormat = "OVA"
+	MediaFormatONA     MediaFormat = "ONA"
 )
 
-const getCurrentlyWatchingQuery = `query($userName:String,$type:MediaType){ 
-	MediaListCollection(userName:$userName,type:$type){
+const (
+	RelationTypePrequel RelationType = "PREQUEL"
+)
+
+const (
+	AiringStatusAiring         AiringStatus = "AIRING"
+	AiringStatusCompleted      AiringStatus = "COMPLETED"
+	AiringStatusFinished       AiringStatus = "FINISHED"
+	AiringStatusNotYetReleased AiringStatus = "NOT_YET_RELEASED"
+)
+
+const getAnimeListQuery = `query($userName:String,$type:MediaType,$statusIn:[MediaListStatus]){ 
+	MediaListCollection(userName:$userName,type:$type,status_in:$statusIn){
 		lists{
 			name
 			entries{
 				status
+				progress
 				media{
+					id
+					idMal
 					startDate{
 						year
 						month
@@ -99,9 +145,13 @@ const getCurrentlyWatchingQuery = `query($userName:String,$type:MediaType){
 					}
 					title{romaji english native}
 					type 
+					format
 					status(version:2)
 					episodes
 					airingSchedule {
+						pageInfo {
+							hasNextPage
+						}
 						edges {
 							node {
 								episode
@@ -109,6 +159,16 @@ const getCurrentlyWatchingQuery = `query($userName:String,$type:MediaType){
 							}
 						}
 					}
+					relations {
+						edges {
+							relationType(version:2)
+							node {
+								id
+								format
+								episodes
+							}
+						}
+					}
 				}
 			}
 		}
@@ -118,10 +178,12 @@ const getCurrentlyWatchingQuery = `query($userName:String,$type:MediaType){
 
 func convertStatus(in ListStatus) animelist.ListStatus {
 	switch in {
-	case ListStatusWatching:
+	case ListStatusWatching, ListStatusRepeating:
 		return animelist.ListStatusWatching
 	case ListStatusCompleted:
 		return animelist.ListStatusCompleted
+	case ListStatusPaused:
+		return animelist.ListStatusOnHold
 	case ListStatusDropped:
 		return animelist.ListStatusDropped
 	case ListStatusPlanning:
@@ -132,16 +194,57 @@ func convertStatus(in ListStatus) animelist.ListStatus {
 	return animelist.ListStatusAll
 }
 
+// convertListStatuses converts animelist statuses into anilist statuses for the status_in filter.
+// It returns nil when all statuses are requested.
+func convertListStatuses(in []animelist.ListStatus) []ListStatus {
+	out := make([]ListStatus, 0, len(in))
+	for _, status := range in {
+		switch status {
+		case animelist.ListStatusWatching:
+			out = append(out, ListStatusWatching, ListStatusRepeating)
+		case animelist.ListStatusCompleted:
+			out = append(out, ListStatusCompleted)
+		case animelist.ListStatusOnHold:
+			out = append(out, ListStatusPaused)
+		case animelist.ListStatusDropped:
+			out = append(out, ListStatusDropped)
+		case animelist.ListStatusPlanToWatch:
+			out = append(out, ListStatusPlanning)
+		case animelist.ListStatusAll:
+			return nil
+		}
+	}
+	return out
+}
+
 func convertAiringStatus(in AiringStatus) animelist.AiringStatus {
 	switch in {
 	case AiringStatusAiring:
 		return animelist.AiringStatusAiring
 	case AiringStatusCompleted, AiringStatusFinished:
 		return animelist.AiringStatusAired
+	case AiringStatusNotYetReleased:
+		return animelist.AiringStatusNotYetAired
 	}
 	return animelist.AiringStatus(-1)
 }
 
+func convertFormat(in MediaFormat) animelist.MediaFormat {
+	switch in {
+	case MediaFormatTV, MediaFormatTVShort:
+		return animelist.MediaFormatTV
+	case MediaFormatMovie:
+		return animelist.MediaFormatMovie
+	case MediaFormatSpecial:
+		return animelist.MediaFormatSpecial
+	case MediaFormatOVA:
+		return animelist.MediaFormatOVA
+	case MediaFormatONA:
+		return animelist.MediaFormatONA
+	}
+	return animelist.MediaFormatUnknown
+}
+
 func convertEntry(in []AnimeListEntry) []animelist.Entry {
 	out := make([]animelist.Entry, 0, len(in))
 	for i := range in {
@@ -156,7 +259,7 @@ func convertEntry(in []AnimeListEntry) []animelist.Entry {
 			})
 		}
 
-		out = append(out, animelist.NewEntry(
+		entry := animelist.NewEntry(
 			[]string{titles.English, titles.Romaji, titles.Native},
 			convertStatus(in[i].Status),
 			convertAiringStatus(in[i].Media.AiringStatus),
@@ -164,23 +267,49 @@ func convertEntry(in []AnimeListEntry) []animelist.Entry {
 			time.Date(in[i].Media.EndDate.Year, time.Month(in[i].Media.EndDate.Month), in[i].Media.EndDate.Day, 0, 0, 0, 0, time.UTC),
 			in[i].Media.Episodes,
 			episodes,
-		))
+		)
+		entry.IDs = animelist.IDs{
+			AniList: in[i].Media.ID,
+			MAL:     in[i].Media.IDMal,
+		}
+		entry.Format = convertFormat(in[i].Media.Format)
+		entry.Season = in[i].season.Season
+		if in[i].season.Season > 0 {
+			entry.SeasonEpisodes = append(slices.Clone(in[i].season.PrequelEpisodes), in[i].Media.Episodes)
+		}
+		entry.Progress = in[i].Progress
+
+		out = append(out, entry)
 	}
 	return out
 }
 
-func (api *API) GetCurrentlyWatching(ctx context.Context) ([]animelist.Entry, error) {
+// GetAnimeList fetches all anime list entries with the given statuses.
+// If no status is given, only currently watching entries are returned.
+func (api *API) GetAnimeList(ctx context.Context, statuses ...animelist.ListStatus) ([]animelist.Entry, error) {
+	if len(statuses) == 0 {
+		statuses = []animelist.ListStatus{animelist.ListStatusWatching}
+	}
+
 	// Check if cache is still valid
-	if len(api.cachedAnimeList) > 0 && time.Now().Before(api.cachedAt.Add(api.cacheTTL)) {
+	if len(api.cachedAnimeList) > 0 &&
+		slices.Equal(api.cachedStatuses, statuses) &&
+		time.Now().Before(api.cachedAt.Add(api.cacheTTL)) {
 		return api.cachedAnimeList, nil
 	}
 
+	variables := map[string]any{
+		"userName": api.Username,
+		"type":     "ANIME",
+	}
+
+	if statusIn := convertListStatuses(statuses); len(statusIn) > 0 {
+		variables["statusIn"] = statusIn
+	}
+
 	reqBody := GraphqlQuery{
-		Query: getCurrentlyWatchingQuery,
-		Variables: map[string]any{
-			"userName": api.Username,
-			"type":     "ANIME",
-		},
+		Query:     getAnimeListQuery,
+		Variables: variables,
 	}
 
 	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodPost, API_URL, bytes.NewReader(utils.Must(json.Marshal(reqBody)))))
@@ -213,14 +342,16 @@ func (api *API) GetCurrentlyWatching(ctx context.Context) ([]animelist.Entry, er
 	out := make([]AnimeListEntry, 0, len(respBody.Data.MediaListCollection.Lists))
 
 	for _, list := range respBody.Data.MediaListCollection.Lists {
-		watchingEntries := utils.Filter(list.Entries, func(entry AnimeListEntry) bool {
-			return entry.Status == ListStatusWatching
-		})
-		out = append(out, watchingEntries...)
+		out = append(out, list.Entries...)
+	}
+
+	for i := range out {
+		api.completeEntry(ctx, &out[i])
 	}
 
 	response := convertEntry(out)
 	api.cachedAnimeList = response
+	api.cachedStatuses = slices.Clone(statuses)
 	api.cachedAt = time.Now()
 
 	return response, nil
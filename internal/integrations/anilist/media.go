@@ -0,0 +1,188 @@
+package anilist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sonalys/animeman/internal/utils"
+)
+
+type (
+	seasonInfo struct {
+		Season int
+		// PrequelEpisodes is the episode count of each previous season, starting from the first season.
+		PrequelEpisodes []int
+	}
+
+	MediaResp struct {
+		Data struct {
+			Media struct {
+				ID             int            `json:"id"`
+				Format         MediaFormat    `json:"format"`
+				Episodes       int            `json:"episodes"`
+				AiringSchedule AiringSchedule `json:"airingSchedule"`
+				Relations      Relations      `json:"relations"`
+			} `json:"Media"`
+		} `json:"data"`
+	}
+)
+
+// maxPrequelDepth avoids walking through endless relation chains.
+const maxPrequelDepth = 30
+
+const getAiringScheduleQuery = `query($id:Int,$page:Int){
+	Media(id:$id){
+		id
+		airingSchedule(page:$page){
+			pageInfo{
+				hasNextPage
+			}
+			edges{
+				node{
+					episode
+					airingAt
+				}
+			}
+		}
+	}
+}
+`
+
+const getRelationsQuery = `query($id:Int){
+	Media(id:$id){
+		id
+		format
+		episodes
+		relations{
+			edges{
+				relationType(version:2)
+				node{
+					id
+					format
+					episodes
+				}
+			}
+		}
+	}
+}
+`
+
+func (f MediaFormat) isSeason() bool {
+	return f == MediaFormatTV || f == MediaFormatTVShort
+}
+
+func (api *API) queryMedia(ctx context.Context, query string, variables map[string]any) (*MediaResp, error) {
+	reqBody := GraphqlQuery{
+		Query:     query,
+		Variables: variables,
+	}
+
+	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodPost, API_URL, bytes.NewReader(utils.Must(json.Marshal(reqBody)))))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("invalid response: %s", string(utils.Must(io.ReadAll(resp.Body))))
+	}
+
+	var respBody MediaResp
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return &respBody, nil
+}
+
+// completeEntry fetches the remaining airing schedule pages and the season information of an entry.
+// Failures are only logged, since the entry is still usable without them.
+func (api *API) completeEntry(ctx context.Context, entry *AnimeListEntry) {
+	logger := log.With().Int("mediaID", entry.Media.ID).Logger()
+
+	schedule := &entry.Media.AiringSchedule
+	for page := 2; schedule.PageInfo.HasNextPage; page++ {
+		resp, err := api.queryMedia(ctx, getAiringScheduleQuery, map[string]any{
+			"id":   entry.Media.ID,
+			"page": page,
+		})
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to fetch anilist airing schedule page")
+			break
+		}
+
+		schedule.Edges = append(schedule.Edges, resp.Data.Media.AiringSchedule.Edges...)
+		schedule.PageInfo = resp.Data.Media.AiringSchedule.PageInfo
+	}
+
+	season, err := api.getSeasonInfo(ctx, entry.Media.ID, entry.Media.Relations)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to fetch anilist prequel relations")
+		return
+	}
+
+	entry.season = season
+}
+
+// findPrequel returns the prequel from the relations, preferring TV seasons over other formats.
+func findPrequel(relations Relations) (id int, format MediaFormat, episodes int, ok bool) {
+	for _, edge := range relations.Edges {
+		if edge.RelationType != RelationTypePrequel {
+			continue
+		}
+
+		if !ok || (edge.Node.Format.isSeason() && !format.isSeason()) {
+			id, format, episodes, ok = edge.Node.ID, edge.Node.Format, edge.Node.Episodes, true
+		}
+	}
+
+	return
+}
+
+// getSeasonInfo walks through the prequel chain of a media, counting previous TV seasons and their episodes.
+// Results are cached by media ID, since relations rarely change.
+func (api *API) getSeasonInfo(ctx context.Context, mediaID int, relations Relations) (seasonInfo, error) {
+	if info, ok := api.seasonCache[mediaID]; ok {
+		return info, nil
+	}
+
+	info := seasonInfo{Season: 1}
+	visited := map[int]struct{}{mediaID: {}}
+
+	for range maxPrequelDepth {
+		prequelID, format, episodes, ok := findPrequel(relations)
+		if !ok {
+			break
+		}
+
+		if _, seen := visited[prequelID]; seen {
+			break
+		}
+		visited[prequelID] = struct{}{}
+
+		if format.isSeason() {
+			info.Season++
+			info.PrequelEpisodes = append([]int{episodes}, info.PrequelEpisodes...)
+		}
+
+		resp, err := api.queryMedia(ctx, getRelationsQuery, map[string]any{"id": prequelID})
+		if err != nil {
+			return seasonInfo{}, err
+		}
+
+		relations = resp.Data.Media.Relations
+	}
+
+	api.seasonCache[mediaID] = info
+
+	return info, nil
+}
@@ -0,0 +1,101 @@
+package anilist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+type (
+	SaveMediaListEntryResp struct {
+		Data struct {
+			SaveMediaListEntry struct {
+				ID       int        `json:"id"`
+				Progress int        `json:"progress"`
+				Status   ListStatus `json:"status"`
+			} `json:"SaveMediaListEntry"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+)
+
+const saveMediaListEntryMutation = `mutation($mediaId:Int,$progress:Int,$status:MediaListStatus){
+	SaveMediaListEntry(mediaId:$mediaId,progress:$progress,status:$status){
+		id
+		progress
+		status
+	}
+}
+`
+
+// UpdateProgress updates the progress and status of an entry in the user's anime list.
+// It requires an access token.
+func (api *API) UpdateProgress(ctx context.Context, entry animelist.Entry, progress int, status animelist.ListStatus) error {
+	if api.accessToken == "" {
+		return fmt.Errorf("anilist access token is not configured")
+	}
+
+	if entry.IDs.AniList == 0 {
+		return fmt.Errorf("entry has no anilist id")
+	}
+
+	statuses := convertListStatuses([]animelist.ListStatus{status})
+	if len(statuses) == 0 {
+		return fmt.Errorf("invalid list status: %s", status)
+	}
+
+	reqBody := GraphqlQuery{
+		Query: saveMediaListEntryMutation,
+		Variables: map[string]any{
+			"mediaId":  entry.IDs.AniList,
+			"progress": progress,
+			"status":   statuses[0],
+		},
+	}
+
+	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodPost, API_URL, bytes.NewReader(utils.Must(json.Marshal(reqBody)))))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+api.accessToken)
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("invalid response: %s", string(utils.Must(io.ReadAll(resp.Body))))
+	}
+
+	var respBody SaveMediaListEntryResp
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if len(respBody.Errors) > 0 {
+		return fmt.Errorf("saving media list entry: %s", respBody.Errors[0].Message)
+	}
+
+	api.updateCachedEntry(entry.IDs.AniList, progress, status)
+
+	return nil
+}
+
+// updateCachedEntry avoids updating the same entry again until the cache expires.
+func (api *API) updateCachedEntry(id, progress int, status animelist.ListStatus) {
+	for i := range api.cachedAnimeList {
+		if api.cachedAnimeList[i].IDs.AniList == id {
+			api.cachedAnimeList[i].Progress = progress
+			api.cachedAnimeList[i].ListStatus = status
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package jellyfin notifies Jellyfin and Emby about new media files, since both share the same API.
+package jellyfin
+
+import (
+	"net/http"
+	"strings"
+)
+
+type (
+	API struct {
+		host   string
+		apiKey string
+		client *http.Client
+	}
+)
+
+// New creates a new API client, authenticated with an API key from the server dashboard.
+func New(client *http.Client, host, apiKey string) *API {
+	return &API{
+		host:   strings.TrimSuffix(host, "/"),
+		apiKey: apiKey,
+		client: client,
+	}
+}
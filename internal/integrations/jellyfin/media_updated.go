@@ -0,0 +1,46 @@
+package jellyfin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sonalys/animeman/internal/utils"
+)
+
+type (
+	MediaUpdate struct {
+		Path       string `json:"Path"`
+		UpdateType string `json:"UpdateType"`
+	}
+
+	MediaUpdatedReq struct {
+		Updates []MediaUpdate `json:"Updates"`
+	}
+)
+
+// RefreshPath notifies the server about new files in the given path, scanning only the libraries containing it.
+func (api *API) RefreshPath(ctx context.Context, path string) error {
+	reqBody := MediaUpdatedReq{
+		Updates: []MediaUpdate{{Path: path, UpdateType: "Created"}},
+	}
+
+	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodPost, api.host+"/Library/Media/Updated", bytes.NewReader(utils.Must(json.Marshal(reqBody)))))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Emby-Token", api.apiKey)
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("invalid response: %d: %s", resp.StatusCode, string(utils.Must(io.ReadAll(resp.Body))))
+	}
+
+	return nil
+}
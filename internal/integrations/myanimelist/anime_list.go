@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -42,38 +43,165 @@ func convertTitles(in ...string) []string {
 	return out
 }
 
+func convertStatus(in ListStatus) animelist.ListStatus {
+	switch in {
+	case ListStatusWatching:
+		return animelist.ListStatusWatching
+	case ListStatusCompleted:
+		return animelist.ListStatusCompleted
+	case ListStatusOnHold:
+		return animelist.ListStatusOnHold
+	case ListStatusDropped:
+		return animelist.ListStatusDropped
+	case ListStatusPlanToWatch:
+		return animelist.ListStatusPlanToWatch
+	}
+	return animelist.ListStatusUnknown
+}
+
+func convertListStatus(in animelist.ListStatus) ListStatus {
+	switch in {
+	case animelist.ListStatusWatching:
+		return ListStatusWatching
+	case animelist.ListStatusCompleted:
+		return ListStatusCompleted
+	case animelist.ListStatusOnHold:
+		return ListStatusOnHold
+	case animelist.ListStatusDropped:
+		return ListStatusDropped
+	case animelist.ListStatusPlanToWatch:
+		return ListStatusPlanToWatch
+	}
+	return ListStatusAll
+}
+
+func convertAiringStatus(in AiringStatus) animelist.AiringStatus {
+	switch in {
+	case AiringStatusAiring:
+		return animelist.AiringStatusAiring
+	case AiringStatusAired:
+		return animelist.AiringStatusAired
+	case AiringStatusNotYetAired:
+		return animelist.AiringStatusNotYetAired
+	}
+	return animelist.AiringStatusUnknown
+}
+
+func convertMediaType(in MediaType) animelist.MediaFormat {
+	switch in {
+	case MediaTypeTV:
+		return animelist.MediaFormatTV
+	case MediaTypeMovie:
+		return animelist.MediaFormatMovie
+	case MediaTypeOVA:
+		return animelist.MediaFormatOVA
+	case MediaTypeONA:
+		return animelist.MediaFormatONA
+	case MediaTypeSpecial, MediaTypeTVSpecial:
+		return animelist.MediaFormatSpecial
+	}
+	return animelist.MediaFormatUnknown
+}
+
 func convertEntry(in []AnimeListEntry) []animelist.Entry {
 	out := make([]animelist.Entry, 0, len(in))
 	timeFormat := findCorrectTimeFormat(in)
 	for i := range in {
-		out = append(out, animelist.NewEntry(
+		entry := animelist.NewEntry(
 			convertTitles(fmt.Sprint(in[i].Title), in[i].TitleEng),
-			animelist.ListStatus(in[i].Status),
-			animelist.AiringStatus(in[i].AiringStatus),
+			convertStatus(in[i].Status),
+			convertAiringStatus(in[i].AiringStatus),
 			utils.Must(time.Parse(timeFormat, in[i].AnimeStartDateString)),
 			utils.Must(time.Parse(timeFormat, in[i].AnimeEndDateString)),
 			in[i].NumEpisodes,
 			nil,
-		))
+		)
+		entry.IDs = animelist.IDs{MAL: in[i].AnimeID}
+		entry.Format = convertMediaType(in[i].MediaType)
+		entry.Progress = in[i].NumWatchedEpisodes
+
+		out = append(out, entry)
 	}
 	return out
 }
 
-func (api *API) GetCurrentlyWatching(ctx context.Context) ([]animelist.Entry, error) {
+// pageSize is the amount of entries returned by each load.json call.
+const pageSize = 300
+
+// GetAnimeList fetches all anime list entries with the given statuses.
+// If no status is given, only currently watching entries are returned.
+func (api *API) GetAnimeList(ctx context.Context, statuses ...animelist.ListStatus) ([]animelist.Entry, error) {
+	if len(statuses) == 0 {
+		statuses = []animelist.ListStatus{animelist.ListStatusWatching}
+	}
+
 	// Check if cache is still valid
-	if len(api.cachedAnimeList) > 0 && time.Now().Before(api.cachedAt.Add(api.cacheTTL)) {
+	if len(api.cachedAnimeList) > 0 &&
+		slices.Equal(api.cachedStatuses, statuses) &&
+		time.Now().Before(api.cachedAt.Add(api.cacheTTL)) {
 		return api.cachedAnimeList, nil
 	}
 
+	malStatuses := utils.Map(statuses, convertListStatus)
+	if slices.Contains(malStatuses, ListStatusAll) {
+		malStatuses = []ListStatus{ListStatusAll}
+	}
+	slices.Sort(malStatuses)
+	malStatuses = slices.Compact(malStatuses)
+
+	var entries []AnimeListEntry
+
+	for _, status := range malStatuses {
+		statusEntries, err := api.listByStatus(ctx, status)
+		if err != nil {
+			if len(api.cachedAnimeList) > 0 {
+				log.
+					Warn().
+					Err(err).
+					Msg("myanimelist.net api errored, using cached response")
+				return api.cachedAnimeList, nil
+			}
+
+			return nil, err
+		}
+
+		entries = append(entries, statusEntries...)
+	}
+
+	api.cachedAnimeList = convertEntry(entries)
+	api.cachedStatuses = slices.Clone(statuses)
+	api.cachedAt = time.Now()
+	return api.cachedAnimeList, nil
+}
+
+// listByStatus fetches all pages of the user's anime list for a given status.
+func (api *API) listByStatus(ctx context.Context, status ListStatus) ([]AnimeListEntry, error) {
 	var path = API_URL + "/animelist/" + api.Username + "/load.json"
 
-	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodGet, path, nil))
-	v := url.Values{
-		"offset": []string{"0"},
-		"status": []string{"1"},
+	var out []AnimeListEntry
+
+	for offset := 0; ; offset += pageSize {
+		req := utils.Must(http.NewRequestWithContext(ctx, http.MethodGet, path, nil))
+		v := url.Values{
+			"offset": []string{fmt.Sprint(offset)},
+		}
+		status.ApplyList(v)
+		req.URL.RawQuery = v.Encode()
+
+		entries, err := api.doList(req)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, entries...)
+
+		if len(entries) < pageSize {
+			return out, nil
+		}
 	}
-	req.URL.RawQuery = v.Encode()
+}
 
+func (api *API) doList(req *http.Request) ([]AnimeListEntry, error) {
 	resp, err := api.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetching response: %w", err)
@@ -81,14 +209,6 @@ func (api *API) GetCurrentlyWatching(ctx context.Context) ([]animelist.Entry, er
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
-		if len(api.cachedAnimeList) > 0 {
-			log.
-				Warn().
-				Err(err).
-				Msg("myanimelist.net api errored, using cached response")
-			return api.cachedAnimeList, nil
-		}
-
 		return nil, fmt.Errorf("invalid response: %s", string(utils.Must(io.ReadAll(resp.Body))))
 	}
 
@@ -97,7 +217,5 @@ func (api *API) GetCurrentlyWatching(ctx context.Context) ([]animelist.Entry, er
 		return nil, fmt.Errorf("reading response: %w", err)
 	}
 
-	api.cachedAnimeList = convertEntry(entries)
-	api.cachedAt = time.Now()
-	return api.cachedAnimeList, nil
+	return entries, nil
 }
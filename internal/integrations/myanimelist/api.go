@@ -12,17 +12,22 @@ const API_URL = "https://myanimelist.net"
 type (
 	API struct {
 		Username        string
+		accessToken     string
 		client          *http.Client
 		cacheTTL        time.Duration
 		cachedAnimeList []animelist.Entry
+		cachedStatuses  []animelist.ListStatus
 		cachedAt        time.Time
 	}
 )
 
-func New(client *http.Client, username string, cacheTTL time.Duration) *API {
+// New creates a new API client.
+// accessToken is optional, and only required for updating list entries.
+func New(client *http.Client, username, accessToken string, cacheTTL time.Duration) *API {
 	return &API{
-		client:   client,
-		Username: username,
-		cacheTTL: cacheTTL,
+		client:      client,
+		Username:    username,
+		accessToken: accessToken,
+		cacheTTL:    cacheTTL,
 	}
 }
@@ -8,16 +8,20 @@ import (
 type (
 	ListStatus   int
 	AiringStatus int
+	MediaType    string
 
 	AnimeListEntry struct {
-		Status ListStatus `json:"status"`
+		AnimeID int        `json:"anime_id"`
+		Status  ListStatus `json:"status"`
 		// Title is any because MAL api sucks. so it sometimes returns int or other types for it.
 		Title                any          `json:"anime_title"`
 		TitleEng             string       `json:"anime_title_eng"`
 		AiringStatus         AiringStatus `json:"anime_airing_status"`
+		MediaType            MediaType    `json:"anime_media_type_string"`
 		AnimeStartDateString string       `json:"anime_start_date_string"`
 		AnimeEndDateString   string       `json:"anime_end_date_string"`
 		NumEpisodes          int          `json:"anime_num_episodes"`
+		NumWatchedEpisodes   int          `json:"num_watched_episodes"`
 	}
 )
 
@@ -31,8 +35,18 @@ const (
 )
 
 const (
-	AiringStatusAired AiringStatus = iota
-	AiringStatusAiring
+	AiringStatusAiring      AiringStatus = 1
+	AiringStatusAired       AiringStatus = 2
+	AiringStatusNotYetAired AiringStatus = 3
+)
+
+const (
+	MediaTypeTV        MediaType = "TV"
+	MediaTypeMovie     MediaType = "Movie"
+	MediaTypeOVA       MediaType = "OVA"
+	MediaTypeONA       MediaType = "ONA"
+	MediaTypeSpecial   MediaType = "Special"
+	MediaTypeTVSpecial MediaType = "TV Special"
 )
 
 var statusNames = []string{
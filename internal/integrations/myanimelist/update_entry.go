@@ -0,0 +1,75 @@
+package myanimelist
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+)
+
+const API_V2_URL = "https://api.myanimelist.net/v2"
+
+var listStatusV2Names = map[animelist.ListStatus]string{
+	animelist.ListStatusWatching:    "watching",
+	animelist.ListStatusCompleted:   "completed",
+	animelist.ListStatusOnHold:      "on_hold",
+	animelist.ListStatusDropped:     "dropped",
+	animelist.ListStatusPlanToWatch: "plan_to_watch",
+}
+
+// UpdateProgress updates the progress and status of an entry in the user's anime list.
+// It requires an access token.
+func (api *API) UpdateProgress(ctx context.Context, entry animelist.Entry, progress int, status animelist.ListStatus) error {
+	if api.accessToken == "" {
+		return fmt.Errorf("myanimelist access token is not configured")
+	}
+
+	if entry.IDs.MAL == 0 {
+		return fmt.Errorf("entry has no myanimelist id")
+	}
+
+	statusName, ok := listStatusV2Names[status]
+	if !ok {
+		return fmt.Errorf("invalid list status: %s", status)
+	}
+
+	var path = fmt.Sprintf("%s/anime/%d/my_list_status", API_V2_URL, entry.IDs.MAL)
+
+	values := url.Values{
+		"status":               []string{statusName},
+		"num_watched_episodes": []string{fmt.Sprint(progress)},
+	}
+
+	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodPatch, path, strings.NewReader(values.Encode())))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Authorization", "Bearer "+api.accessToken)
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("invalid response: %s", string(utils.Must(io.ReadAll(resp.Body))))
+	}
+
+	api.updateCachedEntry(entry.IDs.MAL, progress, status)
+
+	return nil
+}
+
+// updateCachedEntry avoids updating the same entry again until the cache expires.
+func (api *API) updateCachedEntry(id, progress int, status animelist.ListStatus) {
+	for i := range api.cachedAnimeList {
+		if api.cachedAnimeList[i].IDs.MAL == id {
+			api.cachedAnimeList[i].Progress = progress
+			api.cachedAnimeList[i].ListStatus = status
+		}
+	}
+}
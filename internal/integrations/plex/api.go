@@ -0,0 +1,28 @@
+// Package plex requests partial library scans from Plex Media Server.
+package plex
+
+import (
+	"net/http"
+	"strings"
+)
+
+type (
+	API struct {
+		host  string
+		token string
+		// sectionID is the library section to scan. Empty finds the section by it's folders.
+		sectionID string
+		client    *http.Client
+	}
+)
+
+// New creates a new API client, authenticated with a Plex token.
+// sectionID is optional, when empty the section containing the scanned path is used.
+func New(client *http.Client, host, token, sectionID string) *API {
+	return &API{
+		host:      strings.TrimSuffix(host, "/"),
+		token:     token,
+		sectionID: sectionID,
+		client:    client,
+	}
+}
@@ -0,0 +1,90 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/utils"
+)
+
+type (
+	SectionsResp struct {
+		MediaContainer struct {
+			Directory []Section `json:"Directory"`
+		} `json:"MediaContainer"`
+	}
+
+	Section struct {
+		Key      string `json:"key"`
+		Title    string `json:"title"`
+		Location []struct {
+			Path string `json:"path"`
+		} `json:"Location"`
+	}
+)
+
+// RefreshPath requests a partial scan of the given path, instead of the whole library section.
+func (api *API) RefreshPath(ctx context.Context, path string) error {
+	sectionID := api.sectionID
+	if sectionID == "" {
+		var err error
+		if sectionID, err = api.findSection(ctx, path); err != nil {
+			return err
+		}
+	}
+
+	query := url.Values{"path": []string{path}}
+	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodGet, api.host+"/library/sections/"+sectionID+"/refresh?"+query.Encode(), nil))
+	req.Header.Add("X-Plex-Token", api.token)
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("invalid response: %d: %s", resp.StatusCode, string(utils.Must(io.ReadAll(resp.Body))))
+	}
+
+	return nil
+}
+
+// findSection returns the library section with a folder containing the path.
+func (api *API) findSection(ctx context.Context, path string) (string, error) {
+	req := utils.Must(http.NewRequestWithContext(ctx, http.MethodGet, api.host+"/library/sections", nil))
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("X-Plex-Token", api.token)
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching library sections: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("invalid response: %d: %s", resp.StatusCode, string(utils.Must(io.ReadAll(resp.Body))))
+	}
+
+	var respBody SectionsResp
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	for _, section := range respBody.MediaContainer.Directory {
+		for _, location := range section.Location {
+			relative, err := filepath.Rel(location.Path, path)
+			if err == nil && !strings.HasPrefix(relative, "..") {
+				return section.Key, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no library section contains %s", path)
+}
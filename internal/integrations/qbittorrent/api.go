@@ -4,13 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/cookiejar"
-	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+const (
+	// defaultMaxRetries bounds the retries of requests failing with torrentclient.ErrUnavailable.
+	defaultMaxRetries = 5
+	// defaultInitialBackoff is the delay before the first retry, doubled on each retry up to maxBackoff.
+	defaultInitialBackoff = 500 * time.Millisecond
+	maxBackoff            = 10 * time.Second
 )
 
 type (
@@ -18,44 +27,127 @@ type (
 		host               string
 		username, password string
 		client             *http.Client
+		maxRetries         int
+		initialBackoff     time.Duration
 	}
 )
 
-func New(ctx context.Context, host, username, password string) *API {
+// New creates a qBittorrent client. It doesn't connect to qBittorrent, so it can be created while qBittorrent is down.
+func New(host, username, password string) *API {
 	client := &http.Client{
 		Timeout: 3 * time.Second,
 		Jar:     utils.Must(cookiejar.New(nil)),
 	}
-	api := &API{
-		host:     fmt.Sprintf("%s/api/v2", host),
-		username: username,
-		password: password,
-		client:   client,
+	return &API{
+		host:           fmt.Sprintf("%s/api/v2", host),
+		username:       username,
+		password:       password,
+		client:         client,
+		maxRetries:     defaultMaxRetries,
+		initialBackoff: defaultInitialBackoff,
 	}
-	api.Wait(ctx)
-	if version, err := api.Version(ctx); err != nil {
-		log.Fatal().Msgf("failed to connect to qBittorrent: %s", err)
-	} else {
-		log.Info().Msgf("connected to qBittorrent:%s", version)
-	}
-	return api
 }
 
+// Do sends the request, retrying connection failures and unavailable responses with exponential backoff.
+// Forbidden responses log in once and retry, since qBittorrent sessions expire.
+// Error responses are returned as a ResponseError, with their body already closed.
 func (api *API) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return api.do(ctx, req, true, true)
+}
+
+// do sends the request like Do. Connection failures are only retried for idempotent requests,
+// because a request that timed out may still have been handled by qBittorrent.
+func (api *API) do(ctx context.Context, req *http.Request, reauthenticate, idempotent bool) (*http.Response, error) {
+	backoff := api.initialBackoff
+
+	for retry := 0; ; retry++ {
+		resp, err := api.send(ctx, req)
+		switch {
+		case ctx.Err() != nil:
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, ctx.Err()
+		case err != nil && !idempotent:
+			return nil, fmt.Errorf("%w: %w", torrentclient.ErrUnavailable, err)
+		case err != nil:
+			err = fmt.Errorf("%w: %w", torrentclient.ErrUnavailable, err)
+		case resp.StatusCode == http.StatusForbidden && reauthenticate:
+			resp.Body.Close()
+			if err := api.Login(ctx, api.username, api.password); err != nil {
+				return nil, err
+			}
+			reauthenticate = false
+			continue
+		case resp.StatusCode >= 400:
+			err = newResponseError(resp)
+		default:
+			return resp, nil
+		}
+
+		if !errors.Is(err, torrentclient.ErrUnavailable) || retry >= api.maxRetries {
+			return nil, err
+		}
+
+		log.Warn().Msgf("qBittorrent request failed, retrying in %s: %s", backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// send sends a copy of the request, so it's body can be sent again on retries.
+func (api *API) send(ctx context.Context, req *http.Request) (*http.Response, error) {
 	localReq := req.Clone(ctx)
-	resp, err := api.client.Do(localReq)
-	switch {
-	case errors.Is(err, syscall.ECONNREFUSED) ||
-		errors.Is(err, syscall.ECONNABORTED) ||
-		errors.Is(err, syscall.ECONNRESET):
-		log.Warn().Msgf("qBittorrent disconnected")
-		api.Wait(ctx)
-		return api.Do(ctx, req)
-	case err == nil && resp.StatusCode >= 400:
-		if loginErr := api.Login(ctx, api.username, api.password); loginErr != nil {
-			return resp, loginErr
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("copying request body: %w", err)
 		}
-		return api.Do(ctx, req)
+		localReq.Body = body
+	}
+	return api.client.Do(localReq)
+}
+
+// ResponseError is an error response from qBittorrent.
+// It matches the torrentclient errors of it's status code, like torrentclient.ErrNotFound.
+type ResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+func newResponseError(resp *http.Response) *ResponseError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &ResponseError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+}
+
+func (e *ResponseError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("invalid response: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("invalid response: %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *ResponseError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return torrentclient.ErrUnauthorized
+	case e.StatusCode == http.StatusNotFound:
+		return torrentclient.ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return torrentclient.ErrConflict
+	case e.StatusCode >= 500:
+		return torrentclient.ErrUnavailable
+	default:
+		return nil
 	}
-	return resp, err
 }
@@ -0,0 +1,157 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPI creates a client for a test server, retrying twice without waiting.
+func newTestAPI(t *testing.T, handler http.HandlerFunc) *API {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	api := New(server.URL, "user", "password")
+	api.maxRetries = 2
+	api.initialBackoff = time.Millisecond
+	return api
+}
+
+func getVersion(api *API) error {
+	req, err := http.NewRequest(http.MethodGet, api.host+"/app/version", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := api.Do(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantErr  error
+		requests int32
+	}{
+		{name: "ok", status: http.StatusOK, requests: 1},
+		{name: "not found", status: http.StatusNotFound, wantErr: torrentclient.ErrNotFound, requests: 1},
+		{name: "conflict", status: http.StatusConflict, wantErr: torrentclient.ErrConflict, requests: 1},
+		{name: "unavailable", status: http.StatusServiceUnavailable, wantErr: torrentclient.ErrUnavailable, requests: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(tt.status)
+			})
+
+			err := getVersion(api)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.requests, requests.Load())
+		})
+	}
+}
+
+func TestDo_reauthenticate(t *testing.T) {
+	t.Run("expired session", func(t *testing.T) {
+		var logins, requests atomic.Int32
+		api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v2/auth/login" {
+				logins.Add(1)
+				http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+				w.Write([]byte("Ok."))
+				return
+			}
+			requests.Add(1)
+			if _, err := r.Cookie("SID"); err != nil {
+				w.WriteHeader(http.StatusForbidden)
+			}
+		})
+
+		require.NoError(t, getVersion(api))
+		require.Equal(t, int32(1), logins.Load())
+		require.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("forbidden after login", func(t *testing.T) {
+		var logins, requests atomic.Int32
+		api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v2/auth/login" {
+				logins.Add(1)
+				w.Write([]byte("Ok."))
+				return
+			}
+			requests.Add(1)
+			w.WriteHeader(http.StatusForbidden)
+		})
+
+		require.ErrorIs(t, getVersion(api), torrentclient.ErrUnauthorized)
+		require.Equal(t, int32(1), logins.Load())
+		require.Equal(t, int32(2), requests.Load())
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDo_connectionFailure(t *testing.T) {
+	var requests atomic.Int32
+	api := newTestAPI(t, nil)
+	api.client.Transport = roundTripFunc(func(*http.Request) (*http.Response, error) {
+		requests.Add(1)
+		return nil, errors.New("connection refused")
+	})
+
+	require.ErrorIs(t, getVersion(api), torrentclient.ErrUnavailable)
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{name: "ok", body: "Ok."},
+		{name: "wrong credentials", body: "Fails.", wantErr: torrentclient.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				require.Equal(t, "user", r.PostForm.Get("username"))
+				w.Write([]byte(tt.body))
+			})
+
+			err := api.Login(context.Background(), "user", "password")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAddTorrent_timeout(t *testing.T) {
+	var requests atomic.Int32
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	})
+	api.client.Timeout = 10 * time.Millisecond
+
+	err := api.AddTorrent(context.Background(), &torrentclient.AddTorrentConfig{URLs: []string{"magnet:?xt=urn:btih:hash"}})
+	require.ErrorIs(t, err, torrentclient.ErrUnavailable)
+	require.Equal(t, int32(1), requests.Load())
+}
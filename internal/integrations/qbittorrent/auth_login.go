@@ -3,11 +3,15 @@ package qbittorrent
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 )
 
+// Login authenticates the client session. qBittorrent answers wrong credentials with 200 Fails.
 func (api *API) Login(ctx context.Context, username, password string) error {
 	var path = api.host + "/auth/login"
 	data := url.Values{
@@ -19,10 +23,17 @@ func (api *API) Login(ctx context.Context, username, password string) error {
 		return fmt.Errorf("login request creation failed: %w", err)
 	}
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := api.Do(ctx, req)
+	resp, err := api.do(ctx, req, false, true)
 	if err != nil {
 		return fmt.Errorf("login failed: %w", err)
 	}
-	resp.Body.Close()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading login response: %w", err)
+	}
+	if strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("login failed: %w", torrentclient.ErrUnauthorized)
+	}
 	return nil
 }
@@ -29,6 +29,23 @@ func digestArg(arg *torrentclient.AddTorrentConfig) (io.Reader, string) {
 	field = utils.Must(w.CreateFormField("savepath"))
 	utils.Must(io.WriteString(field, fmt.Sprint(arg.SavePath)))
 
+	field = utils.Must(w.CreateFormField("addToTopOfQueue"))
+	utils.Must(io.WriteString(field, fmt.Sprint(arg.TopOfQueue)))
+	field = utils.Must(w.CreateFormField("sequentialDownload"))
+	utils.Must(io.WriteString(field, fmt.Sprint(arg.Sequential)))
+	field = utils.Must(w.CreateFormField("firstLastPiecePrio"))
+	utils.Must(io.WriteString(field, fmt.Sprint(arg.FirstLastPiecePriority)))
+
+	if arg.Limits.Upload > 0 {
+		field = utils.Must(w.CreateFormField("upLimit"))
+		utils.Must(io.WriteString(field, fmt.Sprint(arg.Limits.Upload)))
+	}
+
+	if arg.Limits.Download > 0 {
+		field = utils.Must(w.CreateFormField("dlLimit"))
+		utils.Must(io.WriteString(field, fmt.Sprint(arg.Limits.Download)))
+	}
+
 	if arg.Name != nil {
 		field = utils.Must(w.CreateFormField("rename"))
 		utils.Must(io.WriteString(field, fmt.Sprint(*arg.Name)))
@@ -37,6 +54,8 @@ func digestArg(arg *torrentclient.AddTorrentConfig) (io.Reader, string) {
 	return &b, w.FormDataContentType()
 }
 
+// AddTorrent adds torrents by URL. Connection failures are not retried, since the torrent may have been added
+// before the request timed out. The next discovery finds it by it's tags instead of adding it again.
 func (api *API) AddTorrent(ctx context.Context, arg *torrentclient.AddTorrentConfig) error {
 	var path = api.host + "/torrents/add"
 	r, contentType := digestArg(arg)
@@ -48,7 +67,7 @@ func (api *API) AddTorrent(ctx context.Context, arg *torrentclient.AddTorrentCon
 
 	req.Header.Set("Content-Type", contentType)
 
-	resp, err := api.Do(ctx, req)
+	resp, err := api.do(ctx, req, true, false)
 	if err != nil {
 		return fmt.Errorf("post torrents/add failed: %w", err)
 	}
@@ -0,0 +1,40 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// EnsureCategory creates the category with the save path, or updates the save path of an existing category.
+func (api *API) EnsureCategory(ctx context.Context, name, savePath string) error {
+	err := api.postCategory(ctx, "/torrents/createCategory", name, savePath)
+	if errors.Is(err, torrentclient.ErrConflict) {
+		err = api.postCategory(ctx, "/torrents/editCategory", name, savePath)
+	}
+	return err
+}
+
+func (api *API) postCategory(ctx context.Context, endpoint, name, savePath string) error {
+	var path = api.host + endpoint
+	values := url.Values{
+		"category": []string{name},
+		"savePath": []string{savePath},
+	}
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("category request failed: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,28 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func (api *API) DeleteTorrents(ctx context.Context, hashes []string, deleteFiles bool) error {
+	var path = api.host + "/torrents/delete"
+	values := url.Values{
+		"hashes":      []string{strings.Join(hashes, "|")},
+		"deleteFiles": []string{fmt.Sprint(deleteFiles)},
+	}
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("delete request failed: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
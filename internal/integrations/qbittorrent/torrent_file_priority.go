@@ -0,0 +1,33 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+func (api *API) SetFilePriority(ctx context.Context, hash string, indexes []int, priority torrentclient.FilePriority) error {
+	var path = api.host + "/torrents/filePrio"
+	values := url.Values{
+		"hash":     []string{hash},
+		"id":       []string{strings.Join(utils.Map(indexes, strconv.Itoa), "|")},
+		"priority": []string{strconv.Itoa(int(priority))},
+	}
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("file priority request failed: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
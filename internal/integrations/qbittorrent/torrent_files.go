@@ -0,0 +1,42 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+func (api *API) ListFiles(ctx context.Context, hash string) ([]torrentclient.File, error) {
+	var path = api.host + "/torrents/files"
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list files request failed: %w", err)
+	}
+	req.URL.RawQuery = url.Values{"hash": []string{hash}}.Encode()
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("could not list torrent files: %w", err)
+	}
+	defer resp.Body.Close()
+	rawBody := utils.Must(io.ReadAll(resp.Body))
+	var respBody []File
+	if err := json.Unmarshal(rawBody, &respBody); err != nil {
+		return nil, fmt.Errorf("could not read response: %s: %w", string(rawBody), err)
+	}
+	files := make([]torrentclient.File, 0, len(respBody))
+	for i, f := range respBody {
+		files = append(files, torrentclient.File{
+			Index:    *utils.Coalesce(f.Index, &i),
+			Name:     f.Name,
+			Size:     f.Size,
+			Priority: torrentclient.FilePriority(f.Priority),
+		})
+	}
+	return files, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/sonalys/animeman/internal/utils"
 	"github.com/sonalys/animeman/pkg/v1/torrentclient"
@@ -16,10 +17,25 @@ func convertTorrent(in []Torrent) []torrentclient.Torrent {
 	out := make([]torrentclient.Torrent, 0, len(in))
 	for i := range in {
 		out = append(out, torrentclient.Torrent{
-			Name:     in[i].Name,
-			Category: in[i].Category,
-			Hash:     in[i].Hash,
-			Tags:     in[i].GetTags(),
+			Name:         in[i].Name,
+			Category:     in[i].Category,
+			Hash:         in[i].Hash,
+			Tags:         in[i].GetTags(),
+			Progress:     in[i].Progress,
+			Ratio:        in[i].Ratio,
+			SeedingTime:  time.Duration(in[i].SeedingTime) * time.Second,
+			State:        in[i].GetState(),
+			AddedOn:      unixTime(in[i].AddedOn),
+			LastActivity: unixTime(in[i].LastActivity),
+			SavePath:     in[i].SavePath,
+			Limits: torrentclient.TransferLimits{
+				Upload:   max(in[i].UpLimit, 0),
+				Download: max(in[i].DlLimit, 0),
+			},
+			ShareLimits: torrentclient.ShareLimits{
+				Ratio:       max(in[i].RatioLimit, 0),
+				SeedingTime: time.Duration(max(in[i].SeedingTimeLimit, 0)) * time.Minute,
+			},
 		})
 	}
 	return out
@@ -0,0 +1,29 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SetLocation moves the torrent files into location. qBittorrent moves them in the background.
+func (api *API) SetLocation(ctx context.Context, hashes []string, location string) error {
+	var path = api.host + "/torrents/setLocation"
+	values := url.Values{
+		"hashes":   []string{strings.Join(hashes, "|")},
+		"location": []string{location},
+	}
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("set location request failed: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,42 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// globalShareLimit makes qBittorrent use it's global share limit.
+const globalShareLimit = "-2"
+
+func (api *API) SetShareLimits(ctx context.Context, hashes []string, limits torrentclient.ShareLimits) error {
+	var path = api.host + "/torrents/setShareLimits"
+	values := url.Values{
+		"hashes":                   []string{strings.Join(hashes, "|")},
+		"ratioLimit":               []string{globalShareLimit},
+		"seedingTimeLimit":         []string{globalShareLimit},
+		"inactiveSeedingTimeLimit": []string{globalShareLimit},
+	}
+	if limits.Ratio > 0 {
+		values.Set("ratioLimit", strconv.FormatFloat(limits.Ratio, 'f', -1, 64))
+	}
+	if limits.SeedingTime > 0 {
+		values.Set("seedingTimeLimit", strconv.Itoa(int(limits.SeedingTime.Minutes())))
+	}
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("set share limits request failed: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
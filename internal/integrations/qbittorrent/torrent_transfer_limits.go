@@ -0,0 +1,42 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
+)
+
+// SetTransferLimits sets the upload and download speed limits of torrents. Zero limits remove them.
+func (api *API) SetTransferLimits(ctx context.Context, hashes []string, limits torrentclient.TransferLimits) error {
+	if err := api.setLimit(ctx, "/torrents/setUploadLimit", hashes, limits.Upload); err != nil {
+		return fmt.Errorf("setting upload limit: %w", err)
+	}
+	if err := api.setLimit(ctx, "/torrents/setDownloadLimit", hashes, limits.Download); err != nil {
+		return fmt.Errorf("setting download limit: %w", err)
+	}
+	return nil
+}
+
+func (api *API) setLimit(ctx context.Context, endpoint string, hashes []string, limit int64) error {
+	var path = api.host + endpoint
+	values := url.Values{
+		"hashes": []string{strings.Join(hashes, "|")},
+		"limit":  []string{strconv.FormatInt(limit, 10)},
+	}
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("limit request failed: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := api.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
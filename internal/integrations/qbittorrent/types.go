@@ -3,19 +3,85 @@ package qbittorrent
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sonalys/animeman/internal/utils"
+	"github.com/sonalys/animeman/pkg/v1/torrentclient"
 )
 
 type (
 	Torrent struct {
+		Name     string  `json:"name"`
+		Category string  `json:"category"`
+		Hash     string  `json:"hash"`
+		Tags     string  `json:"tags"`
+		Progress float64 `json:"progress"`
+		Ratio    float64 `json:"ratio"`
+		// SeedingTime is in seconds.
+		SeedingTime int64  `json:"seeding_time"`
+		State       string `json:"state"`
+		// AddedOn and LastActivity are unix timestamps.
+		AddedOn      int64  `json:"added_on"`
+		LastActivity int64  `json:"last_activity"`
+		SavePath     string `json:"save_path"`
+		// UpLimit and DlLimit are in bytes per second. Zero or negative is unlimited.
+		UpLimit int64 `json:"up_limit"`
+		DlLimit int64 `json:"dl_limit"`
+		// RatioLimit and SeedingTimeLimit are the share limits, with the seeding time in minutes.
+		// Negative values use the global limits, or no limit.
+		RatioLimit       float64 `json:"ratio_limit"`
+		SeedingTimeLimit int64   `json:"seeding_time_limit"`
+	}
+
+	File struct {
+		// Index is missing before WebUI API 2.8.2, where files are listed in index order.
+		Index    *int   `json:"index"`
 		Name     string `json:"name"`
-		Category string `json:"category"`
-		Hash     string `json:"hash"`
-		Tags     string `json:"tags"`
+		Size     int64  `json:"size"`
+		Priority int    `json:"priority"`
 	}
 )
 
+// states maps qBittorrent torrent states into torrent client states.
+var states = map[string]torrentclient.State{
+	"downloading":        torrentclient.StateDownloading,
+	"forcedDL":           torrentclient.StateDownloading,
+	"stalledDL":          torrentclient.StateStalled,
+	"metaDL":             torrentclient.StateStalled,
+	"forcedMetaDL":       torrentclient.StateStalled,
+	"uploading":          torrentclient.StateSeeding,
+	"forcedUP":           torrentclient.StateSeeding,
+	"stalledUP":          torrentclient.StateSeeding,
+	"pausedDL":           torrentclient.StatePaused,
+	"pausedUP":           torrentclient.StatePaused,
+	"stoppedDL":          torrentclient.StatePaused,
+	"stoppedUP":          torrentclient.StatePaused,
+	"queuedDL":           torrentclient.StateQueued,
+	"queuedUP":           torrentclient.StateQueued,
+	"checkingDL":         torrentclient.StateChecking,
+	"checkingUP":         torrentclient.StateChecking,
+	"checkingResumeData": torrentclient.StateChecking,
+	"allocating":         torrentclient.StateChecking,
+	"moving":             torrentclient.StateChecking,
+	"error":              torrentclient.StateError,
+	"missingFiles":       torrentclient.StateError,
+}
+
+func (t Torrent) GetState() torrentclient.State {
+	if state, ok := states[t.State]; ok {
+		return state
+	}
+	return torrentclient.StateUnknown
+}
+
+// unixTime converts unix timestamps, keeping unset timestamps as zero.
+func unixTime(timestamp int64) time.Time {
+	if timestamp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(timestamp, 0)
+}
+
 func NewErrConnection(err error) error {
 	return fmt.Errorf("connection error: %w", err)
 }
@@ -0,0 +1,113 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Frieren", want: "Frieren"},
+		{name: "Re:Zero", want: "Re -Zero"},
+		{name: "Fate/Zero", want: "Fate Zero"},
+		{name: "Who?  ", want: "Who"},
+		{name: "Show...", want: "Show"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, SanitizeName(tt.name))
+		})
+	}
+}
+
+func TestEpisodePath(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  tags.Tag
+		want string
+	}{
+		{
+			name: "episode",
+			tag:  tags.SeasonEpisode(1, 5),
+			want: "/media/Show/Season 01/Show - S01E05.mkv",
+		},
+		{
+			name: "multiple episodes",
+			tag:  tags.Tag{Seasons: []int{2}, Episodes: []float64{1, 2}},
+			want: "/media/Show/Season 02/Show - S02E01-E02.mkv",
+		},
+		{
+			name: "special",
+			tag:  tags.SeasonEpisode(0, 1),
+			want: "/media/Show/Season 00/Show - S00E01.mkv",
+		},
+		{
+			name: "half episode",
+			tag:  tags.Tag{Seasons: []int{1}, Episodes: []float64{7.5}},
+			want: "/media/Show/Season 01/Show - S01E7.5.mkv",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, EpisodePath("/media", "Show", tt.tag, "mkv"))
+		})
+	}
+
+	require.Equal(t, "/media/Movie/Movie.mp4", MoviePath("/media", "Movie", "mp4"))
+}
+
+func TestPlace(t *testing.T) {
+	for _, mode := range []Mode{ModeHardlink, ModeCopy, ModeMove} {
+		t.Run(string(mode), func(t *testing.T) {
+			dir := t.TempDir()
+			source := filepath.Join(dir, "downloads", "episode.mkv")
+			destination := filepath.Join(dir, "media", "Show", "Season 01", "Show - S01E01.mkv")
+
+			require.NoError(t, os.MkdirAll(filepath.Dir(source), 0o755))
+			require.NoError(t, os.WriteFile(source, []byte("video"), 0o644))
+
+			require.NoError(t, Place(mode, source, destination))
+
+			content, err := os.ReadFile(destination)
+			require.NoError(t, err)
+			require.Equal(t, "video", string(content))
+
+			_, err = os.Stat(source)
+			require.Equal(t, mode == ModeMove, os.IsNotExist(err))
+
+			require.ErrorIs(t, Place(ModeCopy, destination, destination), ErrExists)
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	for _, mode := range []Mode{ModeHardlink, ModeCopy, ModeMove} {
+		t.Run(string(mode), func(t *testing.T) {
+			dir := t.TempDir()
+			source := filepath.Join(dir, "downloads", "episode v2.mkv")
+			destination := filepath.Join(dir, "media", "Show", "Season 01", "Show - S01E01.mkv")
+
+			require.NoError(t, os.MkdirAll(filepath.Dir(source), 0o755))
+			require.NoError(t, os.MkdirAll(filepath.Dir(destination), 0o755))
+			require.NoError(t, os.WriteFile(source, []byte("video v2"), 0o644))
+			require.NoError(t, os.WriteFile(destination, []byte("video"), 0o644))
+
+			require.NoError(t, Replace(mode, source, destination))
+
+			content, err := os.ReadFile(destination)
+			require.NoError(t, err)
+			require.Equal(t, "video v2", string(content))
+
+			entries, err := os.ReadDir(filepath.Dir(destination))
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+		})
+	}
+}
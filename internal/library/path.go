@@ -0,0 +1,61 @@
+// Package library places downloaded video files into a media library, using the Plex and Jellyfin naming.
+// Example: Show/Season 01/Show - S01E05.mkv.
+package library
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sonalys/animeman/internal/tags"
+)
+
+// invalidNameChars are replaced in file names, since they are not allowed by some file systems.
+var invalidNameChars = strings.NewReplacer(
+	"/", " ",
+	"\\", " ",
+	":", " -",
+	"*", "",
+	"?", "",
+	"\"", "'",
+	"<", "",
+	">", "",
+	"|", " ",
+)
+
+// SanitizeName removes characters not allowed in file names. Example: Re:Zero -> Re -Zero.
+func SanitizeName(name string) string {
+	name = invalidNameChars.Replace(name)
+	name = strings.Join(strings.Fields(name), " ")
+	return strings.Trim(name, " .")
+}
+
+// EpisodePath returns the library path of an episode file, with the season folder.
+// Example: root/Show/Season 01/Show - S01E05.mkv, or Show - S01E01-E02.mkv for files with multiple episodes.
+// Season zero is used by specials, like Season 00/Show - S00E01.mkv.
+func EpisodePath(root, show string, tag tags.Tag, extension string) string {
+	show = SanitizeName(show)
+	season := tag.FirstSeason()
+
+	name := fmt.Sprintf("%s - S%02dE%s", show, season, formatEpisode(tag.FirstEpisode()))
+	if tag.LastEpisode() != tag.FirstEpisode() {
+		name += "-E" + formatEpisode(tag.LastEpisode())
+	}
+
+	return filepath.Join(root, show, fmt.Sprintf("Season %02d", season), name+"."+extension)
+}
+
+// MoviePath returns the library path of a movie file. Example: root/Movie/Movie.mkv.
+func MoviePath(root, movie, extension string) string {
+	movie = SanitizeName(movie)
+	return filepath.Join(root, movie, movie+"."+extension)
+}
+
+// formatEpisode pads whole episodes with a zero, keeping half episodes. Example: 05 and 7.5.
+func formatEpisode(episode float64) string {
+	if episode == float64(int(episode)) {
+		return fmt.Sprintf("%02d", int(episode))
+	}
+	return strconv.FormatFloat(episode, 'f', -1, 64)
+}
@@ -0,0 +1,114 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Mode is how files are placed into the library.
+type Mode string
+
+const (
+	// ModeHardlink links files into the library, keeping the torrent seeding without using extra space.
+	// Both paths must be in the same file system.
+	ModeHardlink Mode = "hardlink"
+	// ModeCopy copies files into the library.
+	ModeCopy Mode = "copy"
+	// ModeMove moves files into the library. The torrent can't seed afterwards.
+	ModeMove Mode = "move"
+)
+
+// ErrExists is returned when the library already has a file at the destination.
+var ErrExists = errors.New("file already exists")
+
+func (m Mode) Validate() error {
+	switch m {
+	case ModeHardlink, ModeCopy, ModeMove:
+		return nil
+	default:
+		return fmt.Errorf("'%s' is invalid. should be [hardlink,copy,move]", m)
+	}
+}
+
+// Place puts the source file at the destination, creating it's directories.
+// Existing destinations are never replaced, returning ErrExists.
+func Place(mode Mode, source, destination string) error {
+	if _, err := os.Lstat(destination); err == nil {
+		return fmt.Errorf("%s: %w", destination, ErrExists)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	switch mode {
+	case ModeHardlink:
+		if err := os.Link(source, destination); err != nil {
+			return fmt.Errorf("linking file: %w", err)
+		}
+	case ModeMove:
+		if err := os.Rename(source, destination); err != nil {
+			return fmt.Errorf("moving file: %w", err)
+		}
+	case ModeCopy:
+		if err := copyFile(source, destination); err != nil {
+			return fmt.Errorf("copying file: %w", err)
+		}
+	default:
+		return mode.Validate()
+	}
+
+	return nil
+}
+
+// Replace puts the source file at the destination, replacing the existing file, like an episode upgraded to a higher release version.
+// The source is placed next to the destination first, then renamed over it, so the library never has a partial file.
+func Replace(mode Mode, source, destination string) error {
+	if mode == ModeMove {
+		if err := os.Rename(source, destination); err != nil {
+			return fmt.Errorf("moving file: %w", err)
+		}
+		return nil
+	}
+
+	temporary := filepath.Join(filepath.Dir(destination), "."+filepath.Base(destination)+".tmp")
+	if err := os.Remove(temporary); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing previous temporary file: %w", err)
+	}
+
+	if err := Place(mode, source, temporary); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temporary, destination); err != nil {
+		os.Remove(temporary)
+		return fmt.Errorf("replacing file: %w", err)
+	}
+
+	return nil
+}
+
+// copyFile copies the source into a new destination file, removing it on failure.
+func copyFile(source, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(destination)
+		return err
+	}
+
+	return out.Close()
+}
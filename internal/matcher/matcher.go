@@ -0,0 +1,145 @@
+package matcher
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sonalys/animeman/internal/parser"
+	"github.com/sonalys/animeman/internal/utils"
+)
+
+// DefaultThreshold is the minimum score for a release to match a title, when none is configured.
+const DefaultThreshold = 0.8
+
+// Result is the best scoring title for a release.
+type Result struct {
+	Title string
+	Score float64
+}
+
+// romanization normalizes the different ways long vowels are romanized.
+// Example: Kyōkai and Kyokai are both normalized to kyokai.
+var romanization = strings.NewReplacer(
+	"ā", "a", "â", "a",
+	"ē", "e", "ê", "e",
+	"ī", "i", "î", "i",
+	"ō", "o", "ô", "o",
+	"ū", "u", "û", "u",
+)
+
+// longVowels normalizes long vowels written with two letters, applied to romaji tokens only.
+// Example: Kyoukai becomes kyokai, while english words like journey are kept.
+var longVowels = strings.NewReplacer("ou", "o", "oo", "o", "uu", "u")
+
+// romajiExpr matches tokens written only with romaji syllables, like kyoukai or shippuuden.
+var romajiExpr = regexp.MustCompile(`^(?:[kstpc]?(?:[kgsztdnhbpmrfjwv]y?|sh|ch|ts)?[aeiou]|n)+$`)
+
+// particles normalizes romanized japanese particles, applied to whole tokens only.
+var particles = map[string]string{
+	"wo": "o",
+	"ha": "wa",
+}
+
+// Normalize lowercases the title, normalizes romanization differences and
+// returns it's tokens, splitting on anything that is not a letter or digit.
+func Normalize(title string) []string {
+	title = strings.ToLower(title)
+	title = strings.NewReplacer("'", "", "’", "").Replace(title)
+	title = romanization.Replace(title)
+
+	tokens := strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, token := range tokens {
+		if replacement, ok := particles[token]; ok {
+			tokens[i] = replacement
+			continue
+		}
+		if romajiExpr.MatchString(token) {
+			tokens[i] = longVowels.Replace(token)
+		}
+	}
+
+	return tokens
+}
+
+// Score returns how similar a release name is to an anime list title, from 0 to 1.
+// Season text is removed from the title, and release tags, like [Group] or (1080p), from the release.
+// Both are compared with and without their subtitle, like Mushoku Tensei: Isekai Ittara Honki Dasu.
+// The score is the highest between the token set similarity and the Levenshtein similarity.
+func Score(title, release string) float64 {
+	title = parser.StripSeason(title)
+	release = parser.StripTitle(release)
+
+	var score float64
+
+	for _, title := range []string{title, parser.StripSubtitle(title)} {
+		for _, release := range []string{release, parser.StripSubtitle(release)} {
+			titleTokens, releaseTokens := Normalize(title), Normalize(release)
+			if len(titleTokens) == 0 || len(releaseTokens) == 0 {
+				continue
+			}
+
+			score = max(
+				score,
+				tokenSetSimilarity(titleTokens, releaseTokens),
+				similarity(strings.Join(titleTokens, " "), strings.Join(releaseTokens, " ")),
+			)
+		}
+	}
+
+	return score
+}
+
+// Best returns the title with the highest score for the release.
+func Best(release string, titles []string) Result {
+	var best Result
+
+	for _, title := range titles {
+		if score := Score(title, release); score > best.Score || best.Title == "" {
+			best = Result{Title: title, Score: score}
+		}
+	}
+
+	return best
+}
+
+// tokenSetSimilarity compares the sorted tokens of both sides, ignoring token order and repetition.
+// Tokens only one side has are penalized on both sides, so a release containing all the title tokens,
+// like Steins;Gate 0 for Gate, doesn't match.
+func tokenSetSimilarity(a, b []string) float64 {
+	a, b = uniqueSorted(a), uniqueSorted(b)
+
+	var intersection, onlyA, onlyB []string
+	for _, token := range a {
+		if _, found := slices.BinarySearch(b, token); found {
+			intersection = append(intersection, token)
+		} else {
+			onlyA = append(onlyA, token)
+		}
+	}
+	for _, token := range b {
+		if _, found := slices.BinarySearch(a, token); !found {
+			onlyB = append(onlyB, token)
+		}
+	}
+
+	common := strings.Join(intersection, " ")
+	withA := strings.TrimSpace(common + " " + strings.Join(onlyA, " "))
+	withB := strings.TrimSpace(common + " " + strings.Join(onlyB, " "))
+
+	return similarity(withA, withB)
+}
+
+func similarity(a, b string) float64 {
+	return utils.CalculateTextSimilarity(a, b, " ")
+}
+
+func uniqueSorted(tokens []string) []string {
+	tokens = slices.Clone(tokens)
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
@@ -0,0 +1,126 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  []string
+	}{
+		{name: "macron", title: "Kyōkai no Kanata", want: []string{"kyokai", "no", "kanata"}},
+		{name: "double vowel", title: "Kyoukai no Kanata", want: []string{"kyokai", "no", "kanata"}},
+		{name: "particle", title: "Ore wo Suki nano wa Omae dake ka yo", want: []string{"ore", "o", "suki", "nano", "wa", "omae", "dake", "ka", "yo"}},
+		{name: "punctuation", title: "Frieren: Beyond Journey's End", want: []string{"frieren", "beyond", "journeys", "end"}},
+		{name: "english words keep double vowels", title: "Journey of Four Souls", want: []string{"journey", "of", "four", "souls"}},
+		{name: "romaji double vowels", title: "Naruto Shippuuden", want: []string{"naruto", "shippuden"}},
+		{name: "cjk", title: "葬送のフリーレン", want: []string{"葬送のフリーレン"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Normalize(tt.title))
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		release string
+		match   bool
+	}{
+		{
+			name:    "exact",
+			title:   "Sousou no Frieren",
+			release: "[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv",
+			match:   true,
+		},
+		{
+			name:    "romanization",
+			title:   "Kyōkai no Kanata",
+			release: "[Group] Kyoukai no Kanata - 01 [1080p]",
+			match:   true,
+		},
+		{
+			name:    "title season is ignored",
+			title:   "Spy x Family Season 2",
+			release: "[Group] Spy x Family - 03 [1080p]",
+			match:   true,
+		},
+		{
+			name:    "title subtitle is ignored",
+			title:   "Mushoku Tensei: Isekai Ittara Honki Dasu",
+			release: "[Group] Mushoku Tensei S2 - 01 [1080p]",
+			match:   true,
+		},
+		{
+			name:    "release with subtitle",
+			title:   "Frieren: Beyond Journey's End",
+			release: "[Group] Frieren - 05 [1080p]",
+			match:   true,
+		},
+		{
+			name:    "word order",
+			title:   "Family Spy",
+			release: "[Group] Spy Family - 05 [1080p]",
+			match:   true,
+		},
+		{
+			name:    "release containing the title",
+			title:   "Gate",
+			release: "[Group] Steins;Gate 0 - 05 [1080p]",
+			match:   false,
+		},
+		{
+			name:    "release starting with the title",
+			title:   "Monster",
+			release: "[Group] Monster Musume - 05 [1080p]",
+			match:   false,
+		},
+		{
+			name:    "typo",
+			title:   "Kusuriya no Hitorigoto",
+			release: "[Group] Kusuriya no Hitorigito - 05 [1080p]",
+			match:   true,
+		},
+		{
+			name:    "different show",
+			title:   "Sousou no Frieren",
+			release: "[Group] Kusuriya no Hitorigoto - 05 [1080p]",
+			match:   false,
+		},
+		{
+			name:    "release contained in title",
+			title:   "Kage no Jitsuryokusha ni Naritakute",
+			release: "[Group] Kage - 05 [1080p]",
+			match:   false,
+		},
+		{
+			name:    "empty release",
+			title:   "Frieren",
+			release: "[Group]",
+			match:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := Score(tt.title, tt.release)
+			require.Equal(t, tt.match, score >= DefaultThreshold, "score: %f", score)
+		})
+	}
+}
+
+func TestBest(t *testing.T) {
+	release := "[Group] Frieren Beyond Journey's End - 05 [1080p]"
+
+	got := Best(release, []string{"Sousou no Frieren", "Frieren: Beyond Journey's End"})
+	require.Equal(t, "Frieren: Beyond Journey's End", got.Title)
+	require.Equal(t, 1.0, got.Score)
+
+	require.Equal(t, Result{}, Best(release, nil))
+}
@@ -0,0 +1,62 @@
+package parser
+
+import "strings"
+
+// cjkNumeral matches arabic, full-width and CJK numerals, like 12, １２ or 十二.
+const cjkNumeral = `[0-9０-９〇零一二两三四五六七八九十百]+`
+
+var cjkDigits = map[rune]int{
+	'〇': 0, '零': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+var cjkMultipliers = map[rune]int{
+	'十': 10,
+	'百': 100,
+}
+
+// parseCJKNumeral parses arabic, full-width and CJK numerals.
+// Examples: 05, ０５, 五, 十二, 二十三, 一〇五.
+func parseCJKNumeral(s string) (int, bool) {
+	s = toHalfWidthDigits(s)
+	if s == "" {
+		return 0, false
+	}
+
+	var total, current int
+	positional := true
+	for _, r := range s {
+		if multiplier, ok := cjkMultipliers[r]; ok {
+			positional = false
+			// A multiplier without digit before it counts as one, like 十二.
+			total += max(current, 1) * multiplier
+			current = 0
+			continue
+		}
+
+		digit, ok := cjkDigits[r]
+		if !ok {
+			if r < '0' || r > '9' {
+				return 0, false
+			}
+			digit = int(r - '0')
+		}
+		// Positional numerals, like 一〇五 or 105, don't use multipliers.
+		current = current*10 + digit
+	}
+
+	if positional {
+		return current, true
+	}
+	return total + current, true
+}
+
+// toHalfWidthDigits replaces full-width digits, like ０５, with arabic ones.
+func toHalfWidthDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '０' && r <= '９' {
+			return '0' + (r - '０')
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCJKNumeral(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{in: "05", want: 5, ok: true},
+		{in: "０５", want: 5, ok: true},
+		{in: "五", want: 5, ok: true},
+		{in: "十", want: 10, ok: true},
+		{in: "十二", want: 12, ok: true},
+		{in: "二十", want: 20, ok: true},
+		{in: "二十三", want: 23, ok: true},
+		{in: "两", want: 2, ok: true},
+		{in: "一百零五", want: 105, ok: true},
+		{in: "一〇五", want: 105, ok: true},
+		{in: "", ok: false},
+		{in: "五話", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := parseCJKNumeral(tt.in)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
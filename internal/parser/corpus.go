@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+type (
+	// CorpusEntry is a release title and it's expected metadata, parsed with season 1 as fallback.
+	// New entries have no expected metadata until filled with UpdateCorpus.
+	CorpusEntry struct {
+		Title string   `json:"title"`
+		Want  Metadata `json:"want"`
+	}
+
+	// CorpusDiff is a metadata field parsed differently than expected.
+	CorpusDiff struct {
+		Title string `json:"title"`
+		Field string `json:"field"`
+		Want  any    `json:"want"`
+		Got   any    `json:"got"`
+	}
+
+	// CorpusReport is the result of parsing a corpus, with the mismatches of each metadata field.
+	CorpusReport struct {
+		Total      int `json:"total"`
+		Mismatches int `json:"mismatches"`
+		// New is the amount of entries without expected metadata, which are not checked.
+		New             int            `json:"new"`
+		FieldMismatches map[string]int `json:"field_mismatches"`
+		Diffs           []CorpusDiff   `json:"diffs"`
+	}
+)
+
+// ReadCorpus reads a golden corpus file.
+func ReadCorpus(path string) ([]CorpusEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading corpus: %w", err)
+	}
+	var entries []CorpusEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("decoding corpus: %w", err)
+	}
+	return entries, nil
+}
+
+// WriteCorpus writes a golden corpus file, indented for reviewing diffs.
+func WriteCorpus(path string, entries []CorpusEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding corpus: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing corpus: %w", err)
+	}
+	return nil
+}
+
+// UpdateCorpus fills the expected metadata of new entries with the current parser output.
+// Entries with expected metadata are kept, so parser changes never rewrite reviewed expectations.
+// Wrong expectations must be corrected by hand.
+func UpdateCorpus(entries []CorpusEntry) []CorpusEntry {
+	out := make([]CorpusEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.isNew() {
+			entry.Want = parseCorpusTitle(entry.Title)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// isNew returns true for entries without expected metadata.
+func (e CorpusEntry) isNew() bool {
+	return reflect.ValueOf(e.Want).IsZero()
+}
+
+// CheckCorpus parses every corpus title, comparing each metadata field with the expected one.
+func CheckCorpus(entries []CorpusEntry) CorpusReport {
+	report := CorpusReport{
+		Total:           len(entries),
+		FieldMismatches: make(map[string]int),
+	}
+
+	for _, entry := range entries {
+		if entry.isNew() {
+			report.New++
+			continue
+		}
+
+		got := reflect.ValueOf(normalizeCorpusMetadata(parseCorpusTitle(entry.Title)))
+		want := reflect.ValueOf(normalizeCorpusMetadata(entry.Want))
+
+		mismatch := false
+		for i := range got.NumField() {
+			gotField, wantField := got.Field(i).Interface(), want.Field(i).Interface()
+			if reflect.DeepEqual(gotField, wantField) {
+				continue
+			}
+
+			field := got.Type().Field(i).Name
+			mismatch = true
+			report.FieldMismatches[field]++
+			report.Diffs = append(report.Diffs, CorpusDiff{
+				Title: entry.Title,
+				Field: field,
+				Want:  wantField,
+				Got:   gotField,
+			})
+		}
+
+		if mismatch {
+			report.Mismatches++
+		}
+	}
+
+	return report
+}
+
+// Accuracy returns the ratio of checked corpus entries parsed exactly as expected.
+func (r CorpusReport) Accuracy() float64 {
+	checked := r.Total - r.New
+	if checked == 0 {
+		return 1
+	}
+	return float64(checked-r.Mismatches) / float64(checked)
+}
+
+// Err returns an error when any corpus entry is parsed differently than expected, or has no expected metadata.
+func (r CorpusReport) Err() error {
+	switch {
+	case r.Mismatches > 0:
+		return fmt.Errorf("%d of %d corpus entries parsed differently than expected", r.Mismatches, r.Total)
+	case r.New > 0:
+		return fmt.Errorf("%d of %d corpus entries have no expected metadata", r.New, r.Total)
+	default:
+		return nil
+	}
+}
+
+func parseCorpusTitle(title string) Metadata {
+	return Parse(title, 1)
+}
+
+// normalizeCorpusMetadata avoids reporting empty and nil slices as differences, since they are lost when encoding.
+func normalizeCorpusMetadata(m Metadata) Metadata {
+	m = m.Clone()
+	if len(m.Labels) == 0 {
+		m.Labels = nil
+	}
+	if len(m.Tag.Seasons) == 0 {
+		m.Tag.Seasons = nil
+	}
+	if len(m.Tag.Episodes) == 0 {
+		m.Tag.Episodes = nil
+	}
+	return m
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// update fills the expected metadata of new corpus titles with the current parser output.
+// New titles can be added to the corpus without metadata, then filled with:
+// go test ./internal/parser -run TestCorpus -update.
+// Existing expectations are never rewritten, wrong ones must be corrected by hand.
+var update = flag.Bool("update", false, "fill the expected metadata of new corpus titles with the current parser output")
+
+const corpusPath = "testdata/corpus.json"
+
+func TestCorpus(t *testing.T) {
+	entries, err := ReadCorpus(corpusPath)
+	require.NoError(t, err)
+
+	if *update {
+		require.NoError(t, WriteCorpus(corpusPath, UpdateCorpus(entries)))
+		return
+	}
+
+	report := CheckCorpus(entries)
+	for _, diff := range report.Diffs {
+		t.Errorf("%s: %s: want %v, got %v", diff.Title, diff.Field, diff.Want, diff.Got)
+	}
+	require.Zero(t, report.New, "corpus titles without expected metadata, fill them with -update and review them")
+}
+
+func TestCheckCorpus(t *testing.T) {
+	entries := UpdateCorpus([]CorpusEntry{
+		{Title: "[SubsPlease] Show - 05 (1080p) [ABCD1234].mkv"},
+		{Title: "[SubsPlease] Show - 06 (1080p) [ABCD1235].mkv"},
+	})
+	entries[1].Want.VerticalResolution = 720
+
+	report := CheckCorpus(entries)
+	require.Equal(t, 2, report.Total)
+	require.Equal(t, 1, report.Mismatches)
+	require.Equal(t, map[string]int{"VerticalResolution": 1}, report.FieldMismatches)
+	require.Equal(t, []CorpusDiff{{
+		Title: entries[1].Title,
+		Field: "VerticalResolution",
+		Want:  720,
+		Got:   1080,
+	}}, report.Diffs)
+	require.Equal(t, 0.5, report.Accuracy())
+	require.Error(t, report.Err())
+}
+
+func TestUpdateCorpus(t *testing.T) {
+	reviewed := CorpusEntry{Title: "[SubsPlease] Show - 05 (1080p) [ABCD1234].mkv"}
+	reviewed.Want = parseCorpusTitle(reviewed.Title)
+	reviewed.Want.Title = "Reviewed"
+
+	entries := UpdateCorpus([]CorpusEntry{
+		reviewed,
+		{Title: "[SubsPlease] Show - 06 (1080p) [ABCD1235].mkv"},
+	})
+
+	require.Equal(t, "Reviewed", entries[0].Want.Title)
+	require.Equal(t, "Show", entries[1].Want.Title)
+
+	report := CheckCorpus([]CorpusEntry{{Title: "[SubsPlease] Show - 07 (1080p) [ABCD1236].mkv"}})
+	require.Equal(t, 1, report.New)
+	require.Error(t, report.Err())
+}
@@ -2,12 +2,13 @@ package parser
 
 import (
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
 
-// Anything that is inside [].
-var tagsExpr = regexp.MustCompile(`\[([^\[\]]*)\]`)
+// Anything that is inside [] or 【】.
+var tagsExpr = regexp.MustCompile(`[\[【]([^\[\]【】]*)[\]】]`)
 
 const episodeRegexExpr = `(\d+(?:\.\d(?:\D|$))?)`
 
@@ -15,20 +16,33 @@ const episodeRegexExpr = `(\d+(?:\.\d(?:\D|$))?)`
 const episodeGroup = `(?:` + episodeRegexExpr + `(?:\s*[~\-]\s*` + episodeRegexExpr + `)?)`
 
 var episodeExpr = []*regexp.Regexp{
+	// 第05話, 第5话, 第十二集 or 第01-12話.
+	regexp.MustCompile(`第(` + cjkNumeral + `)(?:\s*[~\-]\s*(` + cjkNumeral + `))?\s*[話话集回]`),
 	// Title - 05.
 	regexp.MustCompile(` - ` + episodeGroup),
-	// E15 or S02E15.
-	regexp.MustCompile(`(?i)e` + episodeGroup),
+	// E15, S02E15 or S01E01-E12.
+	regexp.MustCompile(`(?i)e` + episodeRegexExpr + `(?:\s*[~\-]\s*e?` + episodeRegexExpr + `)?`),
 	// 0x15.
 	regexp.MustCompile(`(?i)\W\d+x` + episodeGroup),
 }
 
+// Episodes alone inside brackets, used by chinese and japanese groups.
+// Examples: [05], [05v2], [01-12], [12END], 【05】 or (01-12).
+// They are only detected when no other episode format is found, because tags are removed before parsing episodes.
+var bracketEpisodeExpr = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)[\[【](\d{1,3}(?:\.\d)?)(?:\s*[~\-]\s*(\d{1,3}(?:\.\d)?))?(?:v\d+)?(?:\s*(?:end|fin|完))?[\]】]`),
+	// Batches inside parenthesis, like Title (01-12). Single numbers are skipped, because they are usually years.
+	regexp.MustCompile(`\((\d{1,3})\s*[~\-]\s*(\d{1,3})\)`),
+}
+
 func trimNumber(s string) float64 {
-	s = strings.TrimLeft(s, "0")
 	s = strings.Trim(s, " ")
 
 	episodeNumber, err := strconv.ParseFloat(s, 64)
 	if err != nil {
+		if number, ok := parseCJKNumeral(s); ok {
+			return float64(number)
+		}
 		return -1
 	}
 
@@ -37,7 +51,17 @@ func trimNumber(s string) float64 {
 
 // ParseEpisode detects episodes on titles.
 func ParseEpisode(title string) []float64 {
-	for _, expr := range episodeExpr {
+	if episodes := matchEpisode(title, episodeExpr...); len(episodes) > 0 {
+		return episodes
+	}
+
+	// Some scenarios are like Title Season 1, without episodes.
+	return matchEpisode(title, bracketEpisodeExpr...)
+}
+
+// matchEpisode returns the episodes detected by the first matching expression.
+func matchEpisode(title string, exprs ...*regexp.Regexp) []float64 {
+	for _, expr := range exprs {
 		matches := expr.FindAllStringSubmatch(title, -1)
 		if len(matches) == 0 {
 			continue
@@ -55,13 +79,12 @@ func ParseEpisode(title string) []float64 {
 		return []float64{firstEpisode, lastEpisode}
 	}
 
-	// Some scenarios are like Title Season 1
 	return []float64{}
 }
 
 // episodeIndexMatch is used for filtering episodes out of titles.
 func episodeIndexMatch(title string) int {
-	for _, expr := range episodeExpr {
+	for _, expr := range slices.Concat(episodeExpr, bracketEpisodeExpr) {
 		matches := expr.FindAllStringSubmatchIndex(title, -1)
 		if len(matches) == 0 || len(matches[0]) < 2 {
 			continue
@@ -20,8 +20,23 @@ func Test_matchEpisode(t *testing.T) {
 		{name: "-15", title: "show name - 15", episode: []float64{15}, multi: false},
 		{name: "2 - 05", title: "show name 2 - 05", episode: []float64{5}, multi: false},
 		{name: "S02E15", title: "show name S02E15", episode: []float64{15}, multi: false},
+		{name: "S01E01-E24", title: "show name - S01E01-E24 [1080p]", episode: []float64{1, 24}, multi: true},
+		{name: "- 00", title: "show name S2 - 00 (1080p)", episode: []float64{0}, multi: false},
+		{name: "(01-28)", title: "show name (01-28) (1080p) [Batch]", episode: []float64{1, 28}, multi: true},
+		{name: "(2023)", title: "show name (2023) (Season 1) [1080p]", episode: []float64{}, multi: true},
 		{name: "Season", title: "show name Season 2", episode: []float64{}, multi: true},
 		{name: "Season with episode", title: "show name Season 2 - 15", episode: []float64{15}, multi: false},
+		{name: "第05話", title: "葬送のフリーレン 第05話", episode: []float64{5}, multi: false},
+		{name: "第5话", title: "葬送的芙莉莲 第5话", episode: []float64{5}, multi: false},
+		{name: "第十二集", title: "葬送的芙莉莲 第十二集", episode: []float64{12}, multi: false},
+		{name: "第０５話", title: "葬送のフリーレン 第０５話", episode: []float64{5}, multi: false},
+		{name: "第01-12話", title: "葬送のフリーレン 第01-12話", episode: []float64{1, 12}, multi: true},
+		{name: "[05]", title: "[Group][Show][05][1080P]", episode: []float64{5}, multi: false},
+		{name: "【05】", title: "【Group】Show【05】【1080P】", episode: []float64{5}, multi: false},
+		{name: "[05v2]", title: "[Group] Show [05v2][1080P]", episode: []float64{5}, multi: false},
+		{name: "[12END]", title: "[Group] Show [12END][1080P]", episode: []float64{12}, multi: false},
+		{name: "[01-12]", title: "[Group] Show [01-12][1080P]", episode: []float64{1, 12}, multi: true},
+		{name: "bracket resolution", title: "[Group] Show [1080P][2024]", episode: []float64{}, multi: true},
 		{
 			name:    "no episode",
 			title:   "show name S01 1080p WEBRip DD+ x265-EMBER",
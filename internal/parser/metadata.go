@@ -9,14 +9,31 @@ type Metadata struct {
 	Tag                tags.Tag
 	Labels             []string
 	VerticalResolution int
+	// Version is the release revision, like 05v2 or REPACK.
+	// Zero means not specified, which is the first release.
+	Version int
+	// VideoCodec is the normalized video codec: AV1, HEVC or AVC.
+	VideoCodec string
+	// BitDepth is the video bit depth, like 10. Zero means not specified.
+	BitDepth int
+	// AudioCodec is the normalized audio codec: FLAC, OPUS, TRUEHD, DTS, EAC3, AC3 or AAC.
+	AudioCodec string
+	// AudioChannels is the audio channel layout, like 2.0 or 5.1.
+	AudioChannels string
+	DualAudio     bool
+	MultiSub      bool
+	// MediaSource is where the release was ripped from: BD, WEB-DL, WEBRip, WEB, TV or DVD.
+	MediaSource string
+	// StreamingService is the source of web releases, like CR, AMZN or NF.
+	StreamingService string
+	// Container is the file extension, like mkv.
+	Container string
+	// CRC32 is the file checksum, like D834BF79.
+	CRC32 string
 }
 
 func (m Metadata) Clone() Metadata {
-	return Metadata{
-		Source:             m.Source,
-		Title:              m.Title,
-		Tag:                m.Tag,
-		Labels:             append([]string{}, m.Labels...),
-		VerticalResolution: m.VerticalResolution,
-	}
+	out := m
+	out.Labels = append([]string{}, m.Labels...)
+	return out
 }
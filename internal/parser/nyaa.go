@@ -12,19 +12,33 @@ type ParsedNyaa struct {
 	ExtractedMetadata Metadata
 	// Nyaa entry.
 	NyaaTorrent nyaa.Item
+	// Replacement is true for batches replacing downloaded single episodes.
+	Replacement bool
 }
 
-func NewParsedNyaa(animeListEntry animelist.Entry, entry nyaa.Item) ParsedNyaa {
-	fallbackSeason := 1
+// EntrySeason detects the season of an anime list entry.
+// Specials are always season zero.
+// It prefers the season from the anime list relations, then it's titles, defaulting to the first season.
+func EntrySeason(animeListEntry animelist.Entry) int {
+	if animeListEntry.Format == animelist.MediaFormatSpecial {
+		return 0
+	}
+
+	if animeListEntry.Season > 0 {
+		return animeListEntry.Season
+	}
 
 	for _, title := range animeListEntry.Titles {
 		if season := ParseSeason(title); season > 0 {
-			fallbackSeason = season
-			break
+			return season
 		}
 	}
 
-	meta := Parse(entry.Title, fallbackSeason)
+	return 1
+}
+
+func NewParsedNyaa(animeListEntry animelist.Entry, entry nyaa.Item) ParsedNyaa {
+	meta := Parse(entry.Title, EntrySeason(animeListEntry))
 	return ParsedNyaa{
 		ExtractedMetadata: meta,
 		NyaaTorrent:       entry,
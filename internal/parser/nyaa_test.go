@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/sonalys/animeman/pkg/v1/animelist"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EntrySeason(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry animelist.Entry
+		want  int
+	}{
+		{
+			name:  "default",
+			entry: animelist.Entry{Titles: []string{"Show"}},
+			want:  1,
+		},
+		{
+			name:  "from title",
+			entry: animelist.Entry{Titles: []string{"Show 2nd Season"}},
+			want:  2,
+		},
+		{
+			name:  "from relations",
+			entry: animelist.Entry{Titles: []string{"Show: Another Story"}, Season: 3},
+			want:  3,
+		},
+		{
+			name:  "special",
+			entry: animelist.Entry{Titles: []string{"Show 2nd Season Specials"}, Format: animelist.MediaFormatSpecial},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, EntrySeason(tt.entry))
+		})
+	}
+}
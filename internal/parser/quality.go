@@ -5,8 +5,8 @@ import (
 )
 
 var qualityExpr = []*regexp.Regexp{
-	// 1080p, 720p.
-	regexp.MustCompile(`(\d+)p`),
+	// 1080p, 720p, 1080P.
+	regexp.MustCompile(`(?i)(\d+)p`),
 	// 1920x1080
 	regexp.MustCompile(`\d{3,4}x(\d{3,4})`),
 }
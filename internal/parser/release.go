@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// releaseToken matches a pattern delimited by anything but letters and digits, case-insensitively.
+// Release titles separate their attributes with spaces, dots, dashes and brackets.
+func releaseToken(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(?:` + pattern + `)(?:[^\p{L}\p{N}]|$)`)
+}
+
+// releaseAttribute is a normalized value and the patterns detecting it.
+type releaseAttribute struct {
+	value string
+	expr  *regexp.Regexp
+}
+
+// matchAttribute returns the value of the first attribute detected on the title, in order of priority.
+func matchAttribute(title string, attributes []releaseAttribute) string {
+	for _, attribute := range attributes {
+		if attribute.expr.MatchString(title) {
+			return attribute.value
+		}
+	}
+	return ""
+}
+
+// audioChannels is an optional channel layout suffix on audio codecs, like AAC2.0 or DDP5.1.
+const audioChannels = `(?:\s?\d\.\d)?`
+
+var videoCodecs = []releaseAttribute{
+	{value: "AV1", expr: releaseToken(`av1`)},
+	{value: "HEVC", expr: releaseToken(`hevc|x\.?265|h[\s.]?265`)},
+	{value: "AVC", expr: releaseToken(`avc|x\.?264|h[\s.]?264`)},
+}
+
+var audioCodecs = []releaseAttribute{
+	{value: "FLAC", expr: releaseToken(`flac` + audioChannels)},
+	{value: "OPUS", expr: releaseToken(`opus` + audioChannels)},
+	{value: "TRUEHD", expr: releaseToken(`truehd` + audioChannels)},
+	{value: "DTS", expr: releaseToken(`dts(?:-?hd)?(?:[\s.-]?ma)?` + audioChannels)},
+	{value: "EAC3", expr: releaseToken(`e-?ac-?3` + audioChannels + `|ddp` + audioChannels + `|dd\+` + audioChannels)},
+	{value: "AC3", expr: releaseToken(`ac-?3` + audioChannels + `|dd` + audioChannels)},
+	{value: "AAC", expr: releaseToken(`aac` + audioChannels)},
+}
+
+var mediaSources = []releaseAttribute{
+	{value: "BD", expr: releaseToken(`blu-?ray|bd(?:rip|remux)?|bdmv`)},
+	{value: "WEB-DL", expr: releaseToken(`web-?dl`)},
+	{value: "WEBRip", expr: releaseToken(`web-?rip`)},
+	{value: "WEB", expr: releaseToken(`web`)},
+	{value: "TV", expr: releaseToken(`hdtv|tv-?rip`)},
+	{value: "DVD", expr: releaseToken(`dvd(?:rip)?`)},
+}
+
+var streamingServices = []releaseAttribute{
+	{value: "CR", expr: releaseToken(`cr|crunchyroll`)},
+	{value: "AMZN", expr: releaseToken(`amzn|amazon`)},
+	{value: "NF", expr: releaseToken(`nf|netflix`)},
+	{value: "DSNP", expr: releaseToken(`dsnp|disney\+?`)},
+	{value: "HIDIVE", expr: releaseToken(`hidive|hidi`)},
+	{value: "HULU", expr: releaseToken(`hulu`)},
+	{value: "ADN", expr: releaseToken(`adn`)},
+	{value: "BILI", expr: releaseToken(`b-global|bilibili`)},
+}
+
+var (
+	bitDepthExpr      = releaseToken(`(\d{1,2})[\s-]?bits?|hi(10)p?`)
+	audioChannelsExpr = regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}.]|\p{L}\.|aac|flac|opus|ac-?3|ddp?\+?|dts|truehd)([1-7]\.[01])(?:[^\p{L}\p{N}]|$)`)
+	dualAudioExpr     = releaseToken(`dual[\s.-]?audio`)
+	multiSubExpr      = releaseToken(`multi(?:ple)?[\s.-]?sub(?:s|titles?)?`)
+	containerExpr     = regexp.MustCompile(`(?i)\.(mkv|mp4|avi|webm)$`)
+	crc32Expr         = regexp.MustCompile(`[\[(]([0-9A-Fa-f]{8})[\])]`)
+)
+
+// ParseVideoCodec detects the normalized video codec, like HEVC for x265.
+func ParseVideoCodec(title string) string {
+	return matchAttribute(title, videoCodecs)
+}
+
+// ParseBitDepth detects the video bit depth, like 10bit or Hi10P. Zero means not specified.
+func ParseBitDepth(title string) int {
+	matches := bitDepthExpr.FindStringSubmatch(title)
+	if len(matches) < 3 {
+		return 0
+	}
+	return parseInt(matches[1] + matches[2])
+}
+
+// ParseAudioCodec detects the normalized audio codec, like EAC3 for DDP.
+func ParseAudioCodec(title string) string {
+	return matchAttribute(title, audioCodecs)
+}
+
+// ParseAudioChannels detects the audio channel layout, like 2.0 or 5.1.
+func ParseAudioChannels(title string) string {
+	matches := audioChannelsExpr.FindStringSubmatch(title)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// IsDualAudio detects releases with both original and dubbed audio.
+func IsDualAudio(title string) bool {
+	return dualAudioExpr.MatchString(title)
+}
+
+// IsMultiSub detects releases with subtitles in multiple languages.
+func IsMultiSub(title string) bool {
+	return multiSubExpr.MatchString(title)
+}
+
+// ParseMediaSource detects where the release was ripped from, like BD, WEB-DL or TV.
+func ParseMediaSource(title string) string {
+	return matchAttribute(title, mediaSources)
+}
+
+// ParseStreamingService detects the streaming service of web releases, like CR, AMZN or NF.
+func ParseStreamingService(title string) string {
+	return matchAttribute(title, streamingServices)
+}
+
+// ParseContainer detects the file container from the title extension, like mkv.
+func ParseContainer(title string) string {
+	matches := containerExpr.FindStringSubmatch(strings.TrimSpace(title))
+	if len(matches) < 2 {
+		return ""
+	}
+	return strings.ToLower(matches[1])
+}
+
+// ParseCRC32 detects the file checksum release groups add to titles, like [D834BF79].
+// Words made only of hexadecimal letters, like [FACEBEAD], are ignored.
+func ParseCRC32(title string) string {
+	for _, matches := range crc32Expr.FindAllStringSubmatch(title, -1) {
+		if strings.ContainsAny(matches[1], "0123456789") {
+			return strings.ToUpper(matches[1])
+		}
+	}
+	return ""
+}
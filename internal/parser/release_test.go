@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseVideoCodec(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "[Group] Show - 01 [1080p HEVC]", want: "HEVC"},
+		{title: "show name S01 1080p WEBRip DD+ x265-EMBER", want: "HEVC"},
+		{title: "Show.S01E01.1080p.WEB.H.265-GROUP", want: "HEVC"},
+		{title: "[Group] Show - 01 [1080p AV1]", want: "AV1"},
+		{title: "[Group] Show - 01 (1080p x264)", want: "AVC"},
+		{title: "show name S01E13 subtitle 1080p AAC2.0 H 264-VARYG", want: "AVC"},
+		{title: "[Group] Show - 01 [1080p]", want: ""},
+		{title: "[Group] Havc - 01 [1080p]", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseVideoCodec(tt.title))
+		})
+	}
+}
+
+func Test_ParseBitDepth(t *testing.T) {
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{title: "[EMBER] show name (Season 1) [1080p HEVC 10 bits]", want: 10},
+		{title: "[Group] Show - 01 [1080p 10bit]", want: 10},
+		{title: "[Group] Show - 01 [1080p 8-bit]", want: 8},
+		{title: "[Group] Show - 01 [1080p Hi10P]", want: 10},
+		{title: "[Group] Show - 01 [1080p]", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseBitDepth(tt.title))
+		})
+	}
+}
+
+func Test_ParseAudio(t *testing.T) {
+	tests := []struct {
+		title    string
+		codec    string
+		channels string
+	}{
+		{title: "Show.S01E01.1080p.WEB-DL.AAC2.0.H.264-VARYG.mkv", codec: "AAC", channels: "2.0"},
+		{title: "Show.S01E01.1080p.AMZN.WEB-DL.DDP5.1.H.264-GROUP", codec: "EAC3", channels: "5.1"},
+		{title: "show name S01 1080p WEBRip DD+ x265-EMBER", codec: "EAC3", channels: ""},
+		{title: "[Group] Show - 01 [BD 1080p FLAC 2.0]", codec: "FLAC", channels: "2.0"},
+		{title: "[Group] Show - 01 [1080p Opus]", codec: "OPUS", channels: ""},
+		{title: "Show.S01.1080p.BluRay.DTS-HD.MA.5.1.x264-GROUP", codec: "DTS", channels: "5.1"},
+		{title: "Show.S01.1080p.BluRay.TrueHD.7.1.x265-GROUP", codec: "TRUEHD", channels: "7.1"},
+		{title: "Show.S01E01.720p.HDTV.AC3.x264-GROUP", codec: "AC3", channels: ""},
+		{title: "[Group] Show - 07.5 [1080p]", codec: "", channels: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.codec, ParseAudioCodec(tt.title))
+			require.Equal(t, tt.channels, ParseAudioChannels(tt.title))
+		})
+	}
+}
+
+func Test_IsDualAudio_IsMultiSub(t *testing.T) {
+	tests := []struct {
+		title     string
+		dualAudio bool
+		multiSub  bool
+	}{
+		{title: "[Group] Show - 01 [1080p][Dual Audio]", dualAudio: true},
+		{title: "[Group] Show S01 [BD 1080p Dual-Audio]", dualAudio: true},
+		{title: "[Erai-raws] Show - 07 [1080p CR WEB-DL AVC AAC][MultiSub][D834BF79]", multiSub: true},
+		{title: "[Group] Show - 01 [1080p][Multi-Subs][Dual Audio]", dualAudio: true, multiSub: true},
+		{title: "[Erai-raws] Show - 04v2 [1080p][Multiple Subtitle][9CDBA4B6].mkv", multiSub: true},
+		{title: "[Group] Show - 01 [1080p][Multi Subtitles]", multiSub: true},
+		{title: "[Group] Show - 01 [1080p]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.dualAudio, IsDualAudio(tt.title))
+			require.Equal(t, tt.multiSub, IsMultiSub(tt.title))
+		})
+	}
+}
+
+func Test_ParseMediaSource(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "[Group] Show S01 [BD 1080p]", want: "BD"},
+		{title: "Show.S01.1080p.BluRay.x264-GROUP", want: "BD"},
+		{title: "[Group] Show S01 [BDRip 1080p]", want: "BD"},
+		{title: "[Erai-raws] Show - 07 [1080p CR WEB-DL AVC AAC]", want: "WEB-DL"},
+		{title: "show name S01 1080p WEBRip DD+ x265-EMBER", want: "WEBRip"},
+		{title: "Show.S01E01.1080p.WEB.H.265-GROUP", want: "WEB"},
+		{title: "Show.S01E01.720p.HDTV.x264-GROUP", want: "TV"},
+		{title: "[Group] Show - 01 [DVDRip 480p]", want: "DVD"},
+		{title: "[Group] Show - 01 [1080p]", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseMediaSource(tt.title))
+		})
+	}
+}
+
+func Test_ParseStreamingService(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "[Erai-raws] Show - 07 [1080p CR WEB-DL AVC AAC]", want: "CR"},
+		{title: "Show.S01E01.1080p.AMZN.WEB-DL.DDP5.1.H.264-GROUP", want: "AMZN"},
+		{title: "Show.S01E01.1080p.NF.WEB-DL.DDP5.1.x264-GROUP", want: "NF"},
+		{title: "Show.S01E01.1080p.DSNP.WEB-DL.DDP5.1.H.264-GROUP", want: "DSNP"},
+		{title: "[Group] Show - 01 (HIDIVE 1080p)", want: "HIDIVE"},
+		{title: "Show.name.S01E20.1080p.HULU.WEB-DL.AAC2.0.H.264-VARYG.mkv", want: "HULU"},
+		{title: "[Group] Show - 01 (B-Global 1080p)", want: "BILI"},
+		{title: "[Group] Crescent - 01 [1080p]", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseStreamingService(tt.title))
+		})
+	}
+}
+
+func Test_ParseContainer(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "[Group] Show - 01 [1080p].mkv", want: "mkv"},
+		{title: "[Group] Show - 01 [1080p].MP4", want: "mp4"},
+		{title: "[Group] Show - 01 [1080p]", want: ""},
+		{title: "[Group] Show.mkv - 01", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseContainer(tt.title))
+		})
+	}
+}
+
+func Test_ParseCRC32(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "[Erai-raws] Show - 07 [1080p][MultiSub][D834BF79]", want: "D834BF79"},
+		{title: "[Group] Show - 07 (1080p) [9f8a2a07].mkv", want: "9F8A2A07"},
+		{title: "[Group] Show - 07 (1920x1080) [FACEBEAD]", want: ""},
+		{title: "[Group] Show - 07 [1080p]", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseCRC32(tt.title))
+		})
+	}
+}
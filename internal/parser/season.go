@@ -2,58 +2,277 @@ package parser
 
 import (
 	"regexp"
-	"strconv"
+	"strings"
+	"unicode"
 )
 
-var seasonExpr = []*regexp.Regexp{
-	// 2nd season.
-	regexp.MustCompile(`(\d+)(?:(?:nd)|(?:rd)|(?:th))(?i:\sseason)`),
+// unknownSeason marks season annotations without a number, like Final Season or Part 2.
+// They are removed from titles, but the season is detected elsewhere.
+const unknownSeason = -1
+
+type (
+	// seasonToken is a word of a title, delimited by anything but letters and digits.
+	seasonToken struct {
+		text       string
+		start, end int
+	}
+
+	// seasonMatch is a detected season and the title index where it's annotation starts.
+	seasonMatch struct {
+		season int
+		index  int
+	}
+
+	// seasonDetector detects a season annotation at the i-th token.
+	seasonDetector func(title string, tokens []seasonToken, i int) (seasonMatch, bool)
+)
+
+var (
+	// S02, S02E15 or S2E05v2.
+	seasonEpisodeExpr = regexp.MustCompile(`(?i)^s(\d{1,2})(?:e\d+.*)?$`)
 	// 2x15.
-	regexp.MustCompile(`(\d+)(?:x\d+)`),
-	// S02E15.
-	regexp.MustCompile(`(?i:s)(\d+)`),
-	// Season 1.
-	regexp.MustCompile(`(?i:season\s)(\d+)`),
-	// 3 - 04
-	regexp.MustCompile(`(\d+)\s*-\s*(?:\d+)`),
-	// WIP: not working yet, identifies wrongly some cases.
-	// Title 3.
-	// regexp.MustCompile(`(?:[^-]\s+)(\d+)`),
+	seasonCrossExpr = regexp.MustCompile(`(?i)^(\d{1,2})x\d{1,3}$`)
+	// 2nd, 3rd, 4th.
+	ordinalExpr = regexp.MustCompile(`(?i)^(\d{1,2})(?:st|nd|rd|th)$`)
+	// 第2期, 第二期 or 第二季.
+	cjkSeasonExpr = regexp.MustCompile(`第(` + cjkNumeral + `)[期季]`)
+)
+
+var romanNumerals = map[string]int{
+	"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5,
+	"VI": 6, "VII": 7, "VIII": 8, "IX": 9, "X": 10,
+}
+
+// romanTitles are titles ending in a Roman numeral which is part of the name, like Lupin III, keyed by their previous word.
+var romanTitles = map[string]string{
+	"lupin": "III",
+}
+
+// seasonMarkers are words following a title, which annotate the season instead of a title numeral, like Lupin III - Part 6.
+// Season is left out, since it often repeats the numeral, like Overlord IV (Season 4).
+var seasonMarkers = map[string]struct{}{
+	"part": {}, "cour": {},
+}
+
+// seasonDetectors are ordered by priority, explicit annotations first and title heuristics last.
+var seasonDetectors = []seasonDetector{
+	detectEpisodeSeason,
+	detectSeasonWord,
+	detectCJKSeason,
+	detectPartOrCour,
+	detectRomanSeason,
+	detectTitleNumberSeason,
+}
+
+// titleNumberStopWords are words before numbers that are not seasons, like Kaiju No. 8 or Part 2.
+var titleNumberStopWords = map[string]struct{}{
+	"no": {}, "vol": {}, "volume": {}, "ep": {}, "episode": {}, "movie": {}, "film": {},
+	"part": {}, "cour": {}, "season": {}, "seasons": {},
+}
+
+// Explicit season zero, used by specials. Example: S00E01.
+var specialSeasonExpr = regexp.MustCompile(`(?i)\bs0+(?:e\d+(?:\.\d)?)?\b`)
+
+// IsSpecialSeason detects an explicit season zero on titles, used by specials.
+func IsSpecialSeason(title string) bool {
+	return specialSeasonExpr.MatchString(title)
 }
 
 // ParseSeason detects season on titles.
+// Examples: S02E15, 2x15, Season 2, 2nd Season, Season II, 第2期, 第二季, Overlord IV and Title 3 - 04.
+// Annotations without a season number, like Final Season or Part 2, are ignored.
 func ParseSeason(title string) int {
-	for _, expr := range seasonExpr {
-		matches := expr.FindAllStringSubmatch(title, -1)
-		if len(matches) == 0 || len(matches[0]) < 2 {
-			continue
-		}
-
-		seasonNumber, err := strconv.ParseInt(matches[0][1], 10, 64)
-		if err != nil {
-			continue
+	for _, match := range detectSeasons(title) {
+		if match.season != unknownSeason {
+			return match.season
 		}
-
-		return int(seasonNumber)
 	}
 
 	return 0
 }
 
 // seasonIndexMatch is used for removing season from titles.
+// It returns the index of the first season annotation, including the ones without a number.
 func seasonIndexMatch(title string) int {
-	for i, expr := range seasonExpr {
-		matches := expr.FindAllStringSubmatchIndex(title, -1)
-		if len(matches) == 0 || len(matches[0]) < 2 {
-			continue
+	index := -1
+	for _, match := range detectSeasons(title) {
+		if index == -1 || match.index < index {
+			index = match.index
 		}
+	}
+	return index
+}
+
+// detectSeasons returns the first match of each detector, in order of priority.
+func detectSeasons(title string) []seasonMatch {
+	title = strings.TrimSuffix(title, containerExpr.FindString(title))
+	tokens := tokenizeSeason(title)
+
+	var matches []seasonMatch
+	for _, detect := range seasonDetectors {
+		for i := range tokens {
+			if match, ok := detect(title, tokens, i); ok {
+				matches = append(matches, match)
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+func tokenizeSeason(title string) []seasonToken {
+	var tokens []seasonToken
 
-		startingIndex := matches[0][0]
-		if i == 5 {
-			startingIndex = matches[0][2] - 1
+	start := -1
+	for i, r := range title {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start == -1:
+			start = i
+		case !isWord && start != -1:
+			tokens = append(tokens, seasonToken{text: title[start:i], start: start, end: i})
+			start = -1
 		}
+	}
+	if start != -1 {
+		tokens = append(tokens, seasonToken{text: title[start:], start: start, end: len(title)})
+	}
+
+	return tokens
+}
+
+// detectEpisodeSeason detects S02, S02E15 and 2x15.
+func detectEpisodeSeason(_ string, tokens []seasonToken, i int) (seasonMatch, bool) {
+	for _, expr := range []*regexp.Regexp{seasonEpisodeExpr, seasonCrossExpr} {
+		if matches := expr.FindStringSubmatch(tokens[i].text); len(matches) == 2 {
+			return seasonMatch{season: parseInt(matches[1]), index: tokens[i].start}, true
+		}
+	}
+	return seasonMatch{}, false
+}
+
+// detectSeasonWord detects Season 2, Season II, 2nd Season and The Final Season.
+func detectSeasonWord(_ string, tokens []seasonToken, i int) (seasonMatch, bool) {
+	if word := strings.ToLower(tokens[i].text); word != "season" && word != "seasons" {
+		return seasonMatch{}, false
+	}
 
-		return startingIndex
+	if i > 0 {
+		previous := tokens[i-1]
+		if matches := ordinalExpr.FindStringSubmatch(previous.text); len(matches) == 2 {
+			return seasonMatch{season: parseInt(matches[1]), index: previous.start}, true
+		}
+		if strings.EqualFold(previous.text, "final") {
+			if i > 1 && strings.EqualFold(tokens[i-2].text, "the") {
+				previous = tokens[i-2]
+			}
+			return seasonMatch{season: unknownSeason, index: previous.start}, true
+		}
+	}
+
+	if i+1 < len(tokens) {
+		if season, ok := seasonNumber(tokens[i+1].text); ok {
+			return seasonMatch{season: season, index: tokens[i].start}, true
+		}
+	}
+
+	return seasonMatch{}, false
+}
+
+// detectCJKSeason detects 第2期 or 第二季, also when written together with the title.
+func detectCJKSeason(_ string, tokens []seasonToken, i int) (seasonMatch, bool) {
+	matches := cjkSeasonExpr.FindStringSubmatchIndex(tokens[i].text)
+	if len(matches) < 4 {
+		return seasonMatch{}, false
+	}
+	season, ok := parseCJKNumeral(tokens[i].text[matches[2]:matches[3]])
+	if !ok {
+		return seasonMatch{}, false
+	}
+	return seasonMatch{season: season, index: tokens[i].start + matches[0]}, true
+}
+
+// detectPartOrCour detects Part 2, Cour 2 and 2nd Cour.
+// They split a season in halves, so the season number is unknown.
+func detectPartOrCour(_ string, tokens []seasonToken, i int) (seasonMatch, bool) {
+	word := strings.ToLower(tokens[i].text)
+	if word != "part" && word != "cour" {
+		return seasonMatch{}, false
+	}
+
+	if i > 0 && ordinalExpr.MatchString(tokens[i-1].text) {
+		return seasonMatch{season: unknownSeason, index: tokens[i-1].start}, true
+	}
+
+	if i+1 < len(tokens) {
+		if _, ok := seasonNumber(tokens[i+1].text); ok {
+			return seasonMatch{season: unknownSeason, index: tokens[i].start}, true
+		}
+	}
+
+	return seasonMatch{}, false
+}
+
+// detectRomanSeason detects uppercase Roman numerals ending a title, like Overlord IV.
+// I and X are ignored, they are more common as words, like Spy X Family.
+// Numerals which are part of the name, like Lupin III, or followed by a season marker, like Lupin III - Part 6, are not seasons.
+func detectRomanSeason(title string, tokens []seasonToken, i int) (seasonMatch, bool) {
+	season, ok := romanNumerals[tokens[i].text]
+	if !ok || season == 1 || season == 10 || !endsTitle(title, tokens, i) {
+		return seasonMatch{}, false
+	}
+	if romanTitles[strings.ToLower(tokens[i-1].text)] == tokens[i].text {
+		return seasonMatch{}, false
+	}
+	if i+1 < len(tokens) {
+		if _, ok := seasonMarkers[strings.ToLower(tokens[i+1].text)]; ok {
+			return seasonMatch{}, false
+		}
+	}
+	return seasonMatch{season: season, index: tokens[i].start}, true
+}
+
+// detectTitleNumberSeason detects a single digit ending a title, like Title 3 or Title 3 - 04.
+// Numbers with leading zeros, or following a dash, are episodes instead.
+func detectTitleNumberSeason(title string, tokens []seasonToken, i int) (seasonMatch, bool) {
+	text := tokens[i].text
+	if len(text) != 1 || text[0] < '1' || text[0] > '9' || !endsTitle(title, tokens, i) {
+		return seasonMatch{}, false
+	}
+	if _, ok := titleNumberStopWords[strings.ToLower(tokens[i-1].text)]; ok {
+		return seasonMatch{}, false
+	}
+	return seasonMatch{season: parseInt(text), index: tokens[i].start}, true
+}
+
+// endsTitle checks if the i-th token is the last word of a title, following another word.
+// Titles are followed by the end, a dash before the episode, a colon before the subtitle, or an annotation.
+// Dashes joining words, like Kaijuu 8-gou, are part of the title.
+func endsTitle(title string, tokens []seasonToken, i int) bool {
+	if i == 0 || !strings.ContainsFunc(tokens[i-1].text, unicode.IsLetter) {
+		return false
+	}
+
+	if before := title[tokens[i-1].end:tokens[i].start]; strings.Trim(before, " ._") != "" {
+		return false
+	}
+
+	if i+1 == len(tokens) {
+		return strings.TrimSpace(title[tokens[i].end:]) == ""
+	}
+
+	after := strings.TrimLeft(title[tokens[i].end:tokens[i+1].start], " ._")
+	return (strings.HasPrefix(after, "-") && after != "-") || strings.HasPrefix(after, ":") || strings.HasPrefix(after, "(")
+}
+
+// seasonNumber parses the number following season annotations, like 2, 02 or II.
+func seasonNumber(text string) (int, bool) {
+	if season, ok := romanNumerals[strings.ToUpper(text)]; ok {
+		return season, true
+	}
+	if len(text) > 2 || strings.ContainsFunc(text, func(r rune) bool { return r < '0' || r > '9' }) {
+		return 0, false
 	}
-	return -1
+	return parseInt(text), true
 }
@@ -40,6 +40,39 @@ func Test_matchSeason(t *testing.T) {
 			title: "Show.name.S02E19.subtitle.here.1080p.TAG.AAC2.0.H.264-VARYG.mkv",
 			want:  2,
 		},
+		{name: "S2E05v2", title: "Showname S2E05v2", want: 2},
+		{name: "season roman numeral", title: "Showname Season II - 05", want: 2},
+		{name: "season with leading zero", title: "Showname Season 02", want: 2},
+		{name: "title roman numeral", title: "Overlord IV", want: 4},
+		{name: "title roman numeral with episode", title: "[Group] Overlord IV - 05 [1080p].mkv", want: 4},
+		{name: "title roman numeral with subtitle", title: "Sword Art Online II: Phantom Bullet", want: 2},
+		{name: "roman numeral in title", title: "[Group] Lupin III - 05 [1080p]", want: 0},
+		{name: "roman numeral before part", title: "[Group] Lupin III - Part 6 - 05 [1080p]", want: 0},
+		{name: "roman numeral before part with colon", title: "Lupin III: Part 6", want: 0},
+		{name: "roman numeral before season", title: "Showname II - Season 3", want: 3},
+		{name: "title 3", title: "Showname 3", want: 3},
+		{name: "title 3 with subtitle", title: "Showname 3: Subtitle", want: 3},
+		{name: "title 3 with episode", title: "[Group] Showname 3 - 04 (1080p).mkv", want: 3},
+		{name: "japanese season", title: "葬送のフリーレン 第2期", want: 2},
+		{name: "japanese season without spacing", title: "葬送のフリーレン第2期 - 05", want: 2},
+		{name: "chinese season", title: "葬送的芙莉莲 第二季 第05集", want: 2},
+		{name: "japanese season with kanji numeral", title: "葬送のフリーレン 第二期", want: 2},
+		{name: "final season", title: "Shingeki no Kyojin: The Final Season", want: 0},
+		{name: "part", title: "Showname Part 2", want: 0},
+		{name: "season with part", title: "Shingeki no Kyojin Season 3 Part 2", want: 3},
+		{name: "cour", title: "Showname 2nd Cour - 05", want: 0},
+		{name: "cour with season", title: "Showname 2nd Season Cour 2", want: 2},
+		{name: "episode after dash", title: "[Group] Showname - 3 [1080p]", want: 0},
+		{name: "episode batch", title: "[Group] Showname - 01-12 [Batch]", want: 0},
+		{name: "number joined to a word", title: "[Group] Kaijuu 8-gou - 05 [1080p]", want: 0},
+		{name: "number in title", title: "[Group] Kaiju No. 8 - 05 [1080p]", want: 0},
+		{name: "three digit number in title", title: "[Group] Mob Psycho 100 - 05 [1080p]", want: 0},
+		{name: "number starting title", title: "[Group] 86 - Eighty Six - 05", want: 0},
+		{name: "lowercase x", title: "[Group] Spy x Family - 05 [1080p]", want: 0},
+		{name: "uppercase X", title: "[Group] Hunter X Hunter - 05 [1080p]", want: 0},
+		{name: "audio channels", title: "Showname - 05 [1080p DDP 5.1 AAC2.0]", want: 0},
+		{name: "year", title: "Showname (2024) - 05", want: 0},
+		{name: "fraction", title: "Ranma 1/2 - 05", want: 0},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -56,13 +89,71 @@ func Test_seasonIndexMatch(t *testing.T) {
 		want  string
 	}{
 		{title: "title 4th season 2 subtitle", want: "title"},
+		{title: "title season 2 part 2", want: "title"},
+		{title: "title: the final season", want: "title:"},
+		{title: "title final season", want: "title"},
+		{title: "title 2nd cour", want: "title"},
+		{title: "title II - 05", want: "title"},
+		{title: "title IV (01-13) (1080p)", want: "title"},
+		{title: "title 3 - 04", want: "title"},
+		{title: "Lupin III - Part 6 - 05", want: "Lupin III -"},
+		{title: "タイトル第2期", want: "タイトル"},
+		{title: "title S02E05 part 2", want: "title"},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.title, func(t *testing.T) {
-			if got := seasonIndexMatch(tc.title); strings.TrimSpace(tc.title[:got]) != tc.want {
+			got := seasonIndexMatch(tc.title)
+			if got == -1 {
+				t.Fatalf("seasonIndexMatch() = -1, want '%v'", tc.want)
+			}
+			if strings.TrimSpace(tc.title[:got]) != tc.want {
 				t.Errorf("seasonIndexMatch() =	'%v', want '%v'", strings.TrimSpace(tc.title[:got]), tc.want)
 			}
 		})
 	}
 }
+
+func TestParse_romanNumeralTitle(t *testing.T) {
+	tests := []struct {
+		title     string
+		wantTitle string
+		wantTag   string
+	}{
+		{title: "[Group] Lupin III - Part 6 - 05 [1080p]", wantTitle: "Lupin III", wantTag: "S1E5"},
+		{title: "Lupin III: Part 6", wantTitle: "Lupin III", wantTag: "S1"},
+		{title: "[Group] Lupin III - 01 (1080p)", wantTitle: "Lupin III", wantTag: "S1E1"},
+		{title: "[Group] Overlord III - 05 [1080p]", wantTitle: "Overlord", wantTag: "S3E5"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			got := Parse(tc.title, 1)
+			if got.Title != tc.wantTitle || got.Tag.String() != tc.wantTag {
+				t.Errorf("Parse() = '%v' %v, want '%v' %v", got.Title, got.Tag, tc.wantTitle, tc.wantTag)
+			}
+		})
+	}
+}
+
+func TestHasSeason(t *testing.T) {
+	tests := []struct {
+		title string
+		want  bool
+	}{
+		{title: "[Group] Show - 13 [1080p]", want: false},
+		{title: "[Group] Show S02E01 [1080p]", want: true},
+		{title: "[Group] Show 2nd Season - 01 [1080p]", want: true},
+		{title: "[Group][Show 第二季][05][1080p]", want: true},
+		{title: "[Group] Show S00E01 [1080p]", want: true},
+		{title: "[Group] Kaiju No. 8 - 13 [1080p]", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			if got := HasSeason(tc.title); got != tc.want {
+				t.Errorf("HasSeason() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
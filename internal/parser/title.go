@@ -12,6 +12,8 @@ import (
 var titleCleanupExpr = []*regexp.Regexp{
 	// [anything inside brackets] or (parenthesis).
 	regexp.MustCompile(`(\[.*?\])|(\(.*?\))`),
+	// 【group】, 「episode title」 or ★announcement★, used by chinese and japanese groups.
+	regexp.MustCompile(`(【.*?】)|(「.*?」)|(★.*?★)`),
 }
 
 func StripSeason(title string) string {
@@ -25,6 +27,7 @@ func StripSeason(title string) string {
 // StripTitle cleans title from sub-titles, tags and season / episode information.
 // Example: [Source] Show: another story - S03E02 [1080p].mkv -> Show.
 func StripTitle(title string) string {
+	title = strings.TrimSuffix(title, containerExpr.FindString(title))
 	title = removeDotSpacing(title)
 
 	if index := seasonIndexMatch(title); index != -1 {
@@ -35,10 +38,62 @@ func StripTitle(title string) string {
 		title = title[:index]
 	}
 
-	title = StripTags(title)
-	title = strings.TrimSpace(title)
+	title = closeTag(title)
 
-	return title
+	if stripped := StripTags(title); stripped != "" {
+		return trimTitle(stripped)
+	}
+
+	return bracketTitle(title)
+}
+
+// trimTitle removes alternative titles and separators left before the removed season or episode.
+// Examples: Show | Alternative - -> Show, and Show: Part 6 -> Show.
+func trimTitle(title string) string {
+	if index := strings.Index(title, " | "); index != -1 {
+		title = title[:index]
+	}
+	return strings.TrimRight(title, " -:")
+}
+
+// closeTag closes the last tag when season or episode information was removed from inside it.
+// Example: [Source][Show 第二季 -> [Source][Show ], or Show (Season -> Show ().
+func closeTag(title string) string {
+	if index := strings.LastIndex(title, "("); index != -1 && !strings.Contains(title[index:], ")") {
+		title += ")"
+	}
+
+	index := strings.LastIndexAny(title, "[【")
+	if index == -1 || strings.ContainsAny(title[index:], "]】") {
+		return title
+	}
+	if strings.HasPrefix(title[index:], "【") {
+		return title + "】"
+	}
+	return title + "]"
+}
+
+// parseTagSeason detects season inside tags, for titles written only with tags.
+// Example: [Source][Show 第二季][05] -> 2.
+func parseTagSeason(title string) int {
+	for _, tag := range tagsExpr.FindAllStringSubmatch(title, -1) {
+		if season := ParseSeason(tag[1]); season > 0 {
+			return season
+		}
+	}
+	return 0
+}
+
+// bracketTitle returns the first tag after the source, for titles written only with tags.
+// Example: [Source][Show][05][1080p] -> Show.
+func bracketTitle(title string) string {
+	tags := tagsExpr.FindAllStringSubmatch(title, -1)
+	for i := 1; i < len(tags); i++ {
+		if title := strings.TrimSpace(StripSeason(tags[i][1])); title != "" {
+			return title
+		}
+	}
+	return ""
 }
 
 func StripSubtitle(title string) string {
@@ -52,8 +107,14 @@ func StripSubtitle(title string) string {
 
 func removeDotSpacing(title string) string {
 	dotReplaceRegexp := regexp.MustCompile(`([^ ])\.([^ ])`)
-	title = dotReplaceRegexp.ReplaceAllString(title, "$1 $2")
-	return title
+	// Matches can't overlap, so dots separated by a single character, like Spy.x.Family, need another pass.
+	for {
+		replaced := dotReplaceRegexp.ReplaceAllString(title, "$1 $2")
+		if replaced == title {
+			return title
+		}
+		title = replaced
+	}
 }
 
 func StripTags(title string) string {
@@ -64,12 +125,30 @@ func StripTags(title string) string {
 	return strings.TrimSpace(title)
 }
 
+// HasSeason returns true when the title has an explicit season, so Parse doesn't use it's fallback season.
+// Example: Show S02E05 has a season, Show - 05 doesn't.
+func HasSeason(title string) bool {
+	stripped := StripTags(title)
+	return ParseSeason(stripped) > 0 || parseTagSeason(title) > 0 || IsSpecialSeason(stripped)
+}
+
 // Parse will parse a title into a Metadata, extracting stripped title, tags, season and episode information.
 func Parse(title string, fallbackSeason int) Metadata {
 	resp := Metadata{
 		Title:              StripTitle(title),
 		VerticalResolution: parseVerticalResolution(title),
 		Tag:                tags.Tag{},
+		Version:            ParseVersion(title),
+		VideoCodec:         ParseVideoCodec(title),
+		BitDepth:           ParseBitDepth(title),
+		AudioCodec:         ParseAudioCodec(title),
+		AudioChannels:      ParseAudioChannels(title),
+		DualAudio:          IsDualAudio(title),
+		MultiSub:           IsMultiSub(title),
+		MediaSource:        ParseMediaSource(title),
+		StreamingService:   ParseStreamingService(title),
+		Container:          ParseContainer(title),
+		CRC32:              ParseCRC32(title),
 	}
 
 	if tags := tagsExpr.FindAllStringSubmatch(title, -1); len(tags) > 0 {
@@ -80,13 +159,25 @@ func Parse(title string, fallbackSeason int) Metadata {
 		}
 	}
 
+	// Episodes alone inside brackets, and titles written only with tags, are removed with the tags.
+	bracketEpisodes := matchEpisode(title, bracketEpisodeExpr...)
+	tagSeason := parseTagSeason(title)
+
 	title = StripTags(title)
 
 	resp.Tag.Episodes = ParseEpisode(title)
+	if len(resp.Tag.Episodes) == 0 {
+		resp.Tag.Episodes = bracketEpisodes
+	}
 
-	if detectedSeason := ParseSeason(title); detectedSeason > 0 {
+	switch detectedSeason := ParseSeason(title); {
+	case detectedSeason > 0:
 		resp.Tag.Seasons = []int{detectedSeason}
-	} else {
+	case tagSeason > 0:
+		resp.Tag.Seasons = []int{tagSeason}
+	case IsSpecialSeason(title):
+		resp.Tag.Seasons = []int{0}
+	default:
 		resp.Tag.Seasons = []int{fallbackSeason}
 	}
 
@@ -120,9 +211,10 @@ func (t Metadata) BuildSeriesTag() string {
 }
 
 // BuildTorrentTags builds all tags Animeman needs from your torrent client.
+// Movies have no season or episode, so only the series tag is built.
+// Releases with a version keep it in a version tag, like am:v:2, for detecting upgrades.
 func (t Metadata) BuildTorrentTags() []string {
-	tags := []string{t.BuildSeriesTag(), t.Tag.String()}
-	return tags
+	return append([]string{t.BuildSeriesTag()}, tags.Encode(tags.Record{Episode: t.Tag, Version: t.Version})...)
 }
 
 // BuildTitleTag builds the torrent series tag. Example: !serie name.
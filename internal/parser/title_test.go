@@ -25,6 +25,12 @@ func TestTitleParse(t *testing.T) {
 				VerticalResolution: 1080,
 				Source:             "Erai-raws",
 				Labels:             []string{"1080p", "CR", "WEB-DL", "AVC", "AAC", "MultiSub", "D834BF79"},
+				VideoCodec:         "AVC",
+				AudioCodec:         "AAC",
+				MultiSub:           true,
+				MediaSource:        "WEB-DL",
+				StreamingService:   "CR",
+				CRC32:              "D834BF79",
 			},
 		},
 		{
@@ -53,6 +59,8 @@ func TestTitleParse(t *testing.T) {
 				VerticalResolution: 1080,
 				Source:             "Provider",
 				Labels:             []string{"9F8A2A07"},
+				Container:          "mkv",
+				CRC32:              "9F8A2A07",
 			},
 		},
 		{
@@ -79,6 +87,11 @@ func TestTitleParse(t *testing.T) {
 					Episodes: []float64{19},
 				},
 				VerticalResolution: 1080,
+				VideoCodec:         "AVC",
+				AudioCodec:         "AAC",
+				AudioChannels:      "2.0",
+				MediaSource:        "WEB-DL",
+				Container:          "mkv",
 			},
 		},
 		{
@@ -91,6 +104,12 @@ func TestTitleParse(t *testing.T) {
 					Episodes: []float64{20},
 				},
 				VerticalResolution: 1080,
+				VideoCodec:         "AVC",
+				AudioCodec:         "AAC",
+				AudioChannels:      "2.0",
+				MediaSource:        "WEB-DL",
+				StreamingService:   "HULU",
+				Container:          "mkv",
 			},
 		},
 		{
@@ -105,6 +124,77 @@ func TestTitleParse(t *testing.T) {
 				VerticalResolution: -1,
 			},
 		},
+		{
+			name:  "special",
+			title: "show S00E03",
+			want: Metadata{
+				Title: "show",
+				Tag: tags.Tag{
+					Seasons:  []int{0},
+					Episodes: []float64{3},
+				},
+				VerticalResolution: -1,
+			},
+		},
+		{
+			name:  "special tag",
+			title: "S0E3",
+			want: Metadata{
+				Title: "",
+				Tag: tags.Tag{
+					Seasons:  []int{0},
+					Episodes: []float64{3},
+				},
+				VerticalResolution: -1,
+			},
+		},
+		{
+			name:  "cjk brackets",
+			title: "【喵萌奶茶屋】★10月新番★[葬送的芙莉莲 / Sousou no Frieren][05][1080p][简日双语]",
+			want: Metadata{
+				Title: "葬送的芙莉莲 / Sousou no Frieren",
+				Tag: tags.Tag{
+					Seasons:  []int{1},
+					Episodes: []float64{5},
+				},
+				VerticalResolution: 1080,
+				Source:             "喵萌奶茶屋",
+				Labels:             []string{"葬送的芙莉莲", "/", "Sousou", "no", "Frieren", "05", "1080p", "简日双语"},
+			},
+		},
+		{
+			name:  "title only in brackets",
+			title: "[Nekomoe kissaten][Sousou no Frieren 第二季][05v2][1080P][JPSC].mp4",
+			want: Metadata{
+				Title: "Sousou no Frieren",
+				Tag: tags.Tag{
+					Seasons:  []int{2},
+					Episodes: []float64{5},
+				},
+				VerticalResolution: 1080,
+				Source:             "Nekomoe kissaten",
+				Labels:             []string{"Sousou", "no", "Frieren", "第二季", "05v2", "1080P", "JPSC"},
+				Version:            2,
+				Container:          "mp4",
+			},
+		},
+		{
+			name:  "japanese episode title",
+			title: "[Group] 葬送のフリーレン 第05話「魂の眠る地」(BS11 1280x720 x264 AAC).mp4",
+			want: Metadata{
+				Title: "葬送のフリーレン",
+				Tag: tags.Tag{
+					Seasons:  []int{1},
+					Episodes: []float64{5},
+				},
+				VerticalResolution: 720,
+				Source:             "Group",
+				Labels:             []string{},
+				VideoCodec:         "AVC",
+				AudioCodec:         "AAC",
+				Container:          "mp4",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -113,3 +203,34 @@ func TestTitleParse(t *testing.T) {
 		})
 	}
 }
+
+func TestStripTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "[Group] Show (2023) (Season 1) [1080p]", want: "Show"},
+		{title: "[Group] Show - S01E01 [1080p]", want: "Show"},
+		{title: "[Group] Show (BD 1080p) | Alternative", want: "Show"},
+		{title: "[Group] Show IV (01-13) (1080p) [Batch]", want: "Show"},
+		{title: "[Group] Movie (1080p) [A1B2C3D4].mkv", want: "Movie"},
+		{title: "Spy.x.Family.S02E03.1080p.BluRay.x265-GROUP.mkv", want: "Spy x Family"},
+		{title: "Kaiju.No.8.S01E12.1080p.WEB-DL.mkv", want: "Kaiju No 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, StripTitle(tt.title))
+		})
+	}
+}
+
+func TestMetadata_BuildTorrentTags(t *testing.T) {
+	episode := Metadata{Title: "Show", Tag: tags.SeasonEpisode(1, 2)}
+	require.Equal(t, []string{"!show", "am:ep:S01E02"}, episode.BuildTorrentTags())
+
+	upgrade := Metadata{Title: "Show", Tag: tags.SeasonEpisode(1, 2), Version: 2}
+	require.Equal(t, []string{"!show", "am:ep:S01E02", "am:v:2"}, upgrade.BuildTorrentTags())
+
+	movie := Metadata{Title: "Movie"}
+	require.Equal(t, []string{"!movie"}, movie.BuildTorrentTags())
+}
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var versionExpr = []*regexp.Regexp{
+	// 05v2 or S01E05v2.
+	regexp.MustCompile(`(?i)\dv(\d+)\b`),
+	// [v2] or v2.
+	regexp.MustCompile(`(?i)\bv(\d+)\b`),
+}
+
+// Repacks and propers replace a broken release, without specifying a version.
+var repackExpr = regexp.MustCompile(`(?i)\b(?:repack|proper)\b`)
+
+// ParseVersion detects the release version on titles, like 05v2, [v2] or REPACK.
+// It returns zero when no version is specified, meaning the first release.
+func ParseVersion(title string) int {
+	version := 0
+
+	for _, expr := range versionExpr {
+		matches := expr.FindStringSubmatch(title)
+		if len(matches) < 2 {
+			continue
+		}
+
+		if number, err := strconv.Atoi(matches[1]); err == nil {
+			version = number
+			break
+		}
+	}
+
+	if version < 2 && repackExpr.MatchString(title) {
+		version = 2
+	}
+
+	return version
+}
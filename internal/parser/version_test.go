@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseVersion(t *testing.T) {
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{title: "[Group] Show - 05 [1080p]", want: 0},
+		{title: "[Group] Show - 05v2 [1080p]", want: 2},
+		{title: "[Group] Show S01E05v3 [1080p]", want: 3},
+		{title: "[Group] Show - 05 [v2][1080p]", want: 2},
+		{title: "Show.S01E05.REPACK.1080p.WEB.H264", want: 2},
+		{title: "Show.S01E05.PROPER.1080p.WEB.H264", want: 2},
+		{title: "[Group] Show - 05v3 REPACK [1080p]", want: 3},
+		{title: "[Group] Show - 05 [1080p HEVC x265 AAC2.0]", want: 0},
+		{title: "S1E5v2", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseVersion(tt.title))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlockedRelease is a release which should not be downloaded again.
+type BlockedRelease struct {
+	Reason    string    `json:"reason"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+// Blocklist persists the info hashes of dead releases, like torrents stalled without peers.
+type Blocklist struct {
+	mu       sync.RWMutex
+	path     string
+	Releases map[string]BlockedRelease `json:"releases"`
+}
+
+// OpenBlocklist reads the blocklist from path, creating an empty one if it doesn't exist.
+func OpenBlocklist(path string) (*Blocklist, error) {
+	blocklist := &Blocklist{
+		path:     path,
+		Releases: make(map[string]BlockedRelease),
+	}
+	if err := readJSON(path, blocklist); err != nil {
+		return nil, fmt.Errorf("reading blocklist: %w", err)
+	}
+	if blocklist.Releases == nil {
+		blocklist.Releases = make(map[string]BlockedRelease)
+	}
+	return blocklist, nil
+}
+
+// IsBlocked returns true when the info hash was blocked. Info hashes are case insensitive.
+func (b *Blocklist) IsBlocked(hash string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.Releases[strings.ToLower(hash)]
+	return ok
+}
+
+// Block stores the info hash with the reason it was blocked, persisting it.
+func (b *Blocklist) Block(hash, reason string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Releases[strings.ToLower(hash)] = BlockedRelease{
+		Reason:    reason,
+		BlockedAt: time.Now(),
+	}
+	return writeJSON(b.path, b)
+}
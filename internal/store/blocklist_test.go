@@ -0,0 +1,23 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "blocklist.json")
+
+	blocklist, err := OpenBlocklist(path)
+	require.NoError(t, err)
+	require.False(t, blocklist.IsBlocked("abc"))
+
+	require.NoError(t, blocklist.Block("ABC", "stalled"))
+
+	reopened, err := OpenBlocklist(path)
+	require.NoError(t, err)
+	require.True(t, reopened.IsBlocked("abc"))
+	require.Equal(t, "stalled", reopened.Releases["abc"].Reason)
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sonalys/animeman/internal/tags"
+)
+
+// Owned persists the episodes of each show found on disk, without torrents, keyed by the show identity.
+// Movies are stored as a zero tag.
+// The file is read again when it changes, since libraries are imported by another process while the service runs.
+type Owned struct {
+	mu       sync.Mutex
+	path     string
+	modTime  time.Time
+	Episodes map[string][]tags.Tag `json:"episodes"`
+}
+
+// OpenOwned reads the owned episodes from path, creating an empty store if it doesn't exist.
+func OpenOwned(path string) (*Owned, error) {
+	owned := &Owned{
+		path:     path,
+		Episodes: make(map[string][]tags.Tag),
+	}
+	if err := owned.reload(); err != nil {
+		return nil, fmt.Errorf("reading owned episodes: %w", err)
+	}
+	return owned, nil
+}
+
+// reload reads the owned episodes again when the file was modified since it was last read or written.
+func (o *Owned) reload() error {
+	info, err := os.Stat(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", o.path, err)
+	}
+	if info.ModTime().Equal(o.modTime) {
+		return nil
+	}
+
+	var stored struct {
+		Episodes map[string][]tags.Tag `json:"episodes"`
+	}
+	if err := readJSON(o.path, &stored); err != nil {
+		return err
+	}
+
+	o.Episodes = stored.Episodes
+	if o.Episodes == nil {
+		o.Episodes = make(map[string][]tags.Tag)
+	}
+	o.modTime = info.ModTime()
+	return nil
+}
+
+// OwnedEpisodes returns the episodes owned for a show.
+// The previously read episodes are kept when the modified file can't be read.
+func (o *Owned) OwnedEpisodes(key string) []tags.Tag {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_ = o.reload()
+	return slices.Clone(o.Episodes[key])
+}
+
+// AddOwnedEpisodes stores new episodes for a show, persisting them if any was added.
+func (o *Owned) AddOwnedEpisodes(key string, episodes ...tags.Tag) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.reload(); err != nil {
+		return fmt.Errorf("reading owned episodes: %w", err)
+	}
+	added := false
+	for _, episode := range episodes {
+		if slices.ContainsFunc(o.Episodes[key], func(owned tags.Tag) bool { return owned.String() == episode.String() }) {
+			continue
+		}
+		o.Episodes[key] = append(o.Episodes[key], episode)
+		added = true
+	}
+	if !added {
+		return nil
+	}
+	if err := writeJSON(o.path, o); err != nil {
+		return err
+	}
+	if info, err := os.Stat(o.path); err == nil {
+		o.modTime = info.ModTime()
+	}
+	return nil
+}
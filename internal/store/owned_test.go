@@ -0,0 +1,37 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sonalys/animeman/internal/tags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "owned.json")
+
+	owned, err := OpenOwned(path)
+	require.NoError(t, err)
+	require.Empty(t, owned.OwnedEpisodes("anilist:1"))
+
+	require.NoError(t, owned.AddOwnedEpisodes("anilist:1", tags.SeasonEpisode(1, 1), tags.SeasonEpisode(1, 2)))
+	require.NoError(t, owned.AddOwnedEpisodes("anilist:1", tags.SeasonEpisode(1, 2)))
+
+	reopened, err := OpenOwned(path)
+	require.NoError(t, err)
+	require.Equal(t, []tags.Tag{tags.SeasonEpisode(1, 1), tags.SeasonEpisode(1, 2)}, reopened.OwnedEpisodes("anilist:1"))
+
+	t.Run("reloads changes from another process", func(t *testing.T) {
+		importer, err := OpenOwned(path)
+		require.NoError(t, err)
+
+		// Modification times may be coarse, the reload only depends on them changing.
+		require.NoError(t, importer.AddOwnedEpisodes("anilist:2", tags.SeasonEpisode(1, 1)))
+		require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+		require.Equal(t, []tags.Tag{tags.SeasonEpisode(1, 1)}, owned.OwnedEpisodes("anilist:2"))
+	})
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Processed persists the hashes of completed torrents already handled, like imported into the media library.
+type Processed struct {
+	mu     sync.RWMutex
+	path   string
+	Hashes map[string]time.Time `json:"hashes"`
+	// Files maps the library destination of imported files to their release version, for replacing them with upgrades.
+	Files map[string]int `json:"files,omitempty"`
+}
+
+// OpenProcessed reads the processed torrents from path, creating an empty store if it doesn't exist.
+func OpenProcessed(path string) (*Processed, error) {
+	processed := &Processed{
+		path:   path,
+		Hashes: make(map[string]time.Time),
+		Files:  make(map[string]int),
+	}
+	if err := readJSON(path, processed); err != nil {
+		return nil, fmt.Errorf("reading processed torrents: %w", err)
+	}
+	if processed.Hashes == nil {
+		processed.Hashes = make(map[string]time.Time)
+	}
+	if processed.Files == nil {
+		processed.Files = make(map[string]int)
+	}
+	return processed, nil
+}
+
+// IsProcessed returns true when the torrent was already handled. Hashes are case insensitive.
+func (p *Processed) IsProcessed(hash string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.Hashes[strings.ToLower(hash)]
+	return ok
+}
+
+// MarkProcessed stores the torrent as handled, persisting it.
+func (p *Processed) MarkProcessed(hash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Hashes[strings.ToLower(hash)] = time.Now()
+	return writeJSON(p.path, p)
+}
+
+// ImportedVersion returns the release version of the file imported at the library destination.
+func (p *Processed) ImportedVersion(destination string) (int, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	version, ok := p.Files[filepath.Clean(destination)]
+	return version, ok
+}
+
+// MarkImported stores the release version of the file imported at the library destination, persisting it.
+func (p *Processed) MarkImported(destination string, version int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Files[filepath.Clean(destination)] = version
+	return writeJSON(p.path, p)
+}
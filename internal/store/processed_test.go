@@ -0,0 +1,30 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "processed.json")
+
+	processed, err := OpenProcessed(path)
+	require.NoError(t, err)
+	require.False(t, processed.IsProcessed("abc"))
+
+	require.NoError(t, processed.MarkProcessed("ABC"))
+	require.NoError(t, processed.MarkImported("/media/Show/Season 01/Show - S01E05.mkv", 2))
+
+	reopened, err := OpenProcessed(path)
+	require.NoError(t, err)
+	require.True(t, reopened.IsProcessed("abc"))
+
+	version, ok := reopened.ImportedVersion("/media/Show/Season 01/Show - S01E05.mkv")
+	require.True(t, ok)
+	require.Equal(t, 2, version)
+
+	_, ok = reopened.ImportedVersion("/media/Show/Season 01/Show - S01E06.mkv")
+	require.False(t, ok)
+}
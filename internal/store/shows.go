@@ -0,0 +1,48 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Shows persists the torrent title tag used by each show, keyed by the show identity.
+// It allows detecting renamed shows, keeping their episode history.
+type Shows struct {
+	mu        sync.RWMutex
+	path      string
+	TitleTags map[string]string `json:"title_tags"`
+}
+
+// OpenShows reads the shows store from path, creating an empty one if it doesn't exist.
+func OpenShows(path string) (*Shows, error) {
+	shows := &Shows{
+		path:      path,
+		TitleTags: make(map[string]string),
+	}
+	if err := readJSON(path, shows); err != nil {
+		return nil, fmt.Errorf("reading shows: %w", err)
+	}
+	if shows.TitleTags == nil {
+		shows.TitleTags = make(map[string]string)
+	}
+	return shows, nil
+}
+
+// GetTitleTag returns the title tag stored for a show.
+func (s *Shows) GetTitleTag(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tag, ok := s.TitleTags[key]
+	return tag, ok
+}
+
+// SetTitleTag stores the title tag for a show, persisting it if changed.
+func (s *Shows) SetTitleTag(key, tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.TitleTags[key] == tag {
+		return nil
+	}
+	s.TitleTags[key] = tag
+	return writeJSON(s.path, s)
+}
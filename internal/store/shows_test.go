@@ -0,0 +1,27 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "shows.json")
+
+	shows, err := OpenShows(path)
+	require.NoError(t, err)
+
+	_, ok := shows.GetTitleTag("anilist:1")
+	require.False(t, ok)
+
+	require.NoError(t, shows.SetTitleTag("anilist:1", "!cowboy bebop"))
+
+	reopened, err := OpenShows(path)
+	require.NoError(t, err)
+
+	tag, ok := reopened.GetTitleTag("anilist:1")
+	require.True(t, ok)
+	require.Equal(t, "!cowboy bebop", tag)
+}
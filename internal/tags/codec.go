@@ -0,0 +1,204 @@
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Namespace prefixes every tag managed by animeman, keeping them apart from user tags.
+const Namespace = "am:"
+
+const (
+	showPrefix    = Namespace + "show:"
+	episodePrefix = Namespace + "ep:"
+	versionPrefix = Namespace + "v:"
+	// titlePrefix starts the title tags animeman created before show identities. Example: !show.
+	titlePrefix = "!"
+)
+
+// ReplacementTag marks batches downloaded for replacing single episodes. Example: am:replacement.
+const ReplacementTag = Namespace + "replacement"
+
+// Record is the animeman state of a torrent, stored in it's tags.
+// Example: [am:show:anilist:154587, am:ep:S01E05, am:v:2].
+type Record struct {
+	// Show is the show identity, like anilist:154587. Empty when unknown.
+	Show string
+	// Episode is the season and episodes of the torrent. Zero for movies.
+	Episode Tag
+	// Version is the release version, like 2 for 05v2. Zero when not specified.
+	Version int
+	// Replacement is true for batches replacing downloaded single episodes.
+	Replacement bool
+}
+
+// Season episode tags, with optional season ranges, episode ranges and versions.
+// Examples: S01E05, S1E7.5, S01E01-12, S1E1~13, S01-02 and S1E5v2.
+const episodeTagExpr = `S(\d+)(?:-(\d+))?(?:E(\d+(?:\.\d+)?)(?:[-~](\d+(?:\.\d+)?))?)?(?:v(\d+))?`
+
+var (
+	episodeTagRegexp = regexp.MustCompile(`(?i)^` + episodeTagExpr + `$`)
+	// Tags created before namespaced tags, exactly as animeman built them. Examples: S1E5, S1E1-13 and S1E5v2.
+	// Seasons alone, like S2, are left out since they are common user tags.
+	legacyEpisodeTagRegexp = regexp.MustCompile(`^S(\d+)(?:-(\d+))?E(\d+(?:\.\d+)?)(?:-(\d+(?:\.\d+)?))?(?:v(\d+))?$`)
+)
+
+// ShowTag builds the show identity tag. Example: am:show:anilist:154587.
+func ShowTag(show string) string {
+	return showPrefix + show
+}
+
+// EpisodeTag builds the season episode tag. Example: am:ep:S01E05.
+func EpisodeTag(t Tag) string {
+	var b strings.Builder
+
+	b.WriteString(episodePrefix)
+
+	switch {
+	case len(t.Seasons) == 1:
+		fmt.Fprintf(&b, "S%02d", t.Seasons[0])
+	case len(t.Seasons) == 2:
+		fmt.Fprintf(&b, "S%02d-%02d", t.Seasons[0], t.Seasons[1])
+	}
+
+	switch {
+	case len(t.Episodes) == 1:
+		fmt.Fprintf(&b, "E%s", formatEpisode(t.Episodes[0]))
+	case len(t.Episodes) == 2:
+		fmt.Fprintf(&b, "E%s-%s", formatEpisode(t.Episodes[0]), formatEpisode(t.Episodes[1]))
+	}
+
+	return b.String()
+}
+
+// VersionTag builds the release version tag. Example: am:v:2.
+func VersionTag(version int) string {
+	return versionPrefix + strconv.Itoa(version)
+}
+
+// Encode builds the tags of a record.
+// Unknown shows, movies without episodes and first releases have no tag.
+func Encode(r Record) []string {
+	var out []string
+
+	if r.Show != "" {
+		out = append(out, ShowTag(r.Show))
+	}
+
+	if !r.Episode.IsZero() {
+		out = append(out, EpisodeTag(r.Episode))
+	}
+
+	if r.Version > 1 {
+		out = append(out, VersionTag(r.Version))
+	}
+
+	if r.Replacement {
+		out = append(out, ReplacementTag)
+	}
+
+	return out
+}
+
+// Decode reads the record from torrent tags, in any order, ignoring user tags.
+// Torrents without a namespaced episode tag fall back to their legacy tag, like S1E5v2.
+func Decode(torrentTags []string) Record {
+	var r Record
+
+	hasEpisode := false
+	for _, tag := range torrentTags {
+		switch {
+		case strings.HasPrefix(tag, showPrefix):
+			r.Show = strings.TrimPrefix(tag, showPrefix)
+		case strings.HasPrefix(tag, episodePrefix):
+			if episode, _, ok := parseEpisodeTag(episodeTagRegexp, strings.TrimPrefix(tag, episodePrefix)); ok {
+				r.Episode = episode
+				hasEpisode = true
+			}
+		case strings.HasPrefix(tag, versionPrefix):
+			if version, err := strconv.Atoi(strings.TrimPrefix(tag, versionPrefix)); err == nil {
+				r.Version = version
+			}
+		case tag == ReplacementTag:
+			r.Replacement = true
+		}
+	}
+
+	if hasEpisode {
+		return r
+	}
+
+	// Legacy tags are usually the last one, since they were added after the title tag.
+	legacy := LegacyTags(torrentTags)
+	if len(legacy) == 0 {
+		return r
+	}
+
+	r.Episode, r.Version, _ = parseEpisodeTag(legacyEpisodeTagRegexp, legacy[len(legacy)-1])
+
+	return r
+}
+
+// LegacyTags returns the season episode tags created before namespaced tags. Example: S1E5v2.
+// Only torrents tagged by animeman without a namespaced episode tag have them, which are
+// torrents with the legacy tag alone or with a title tag, like [!show, S1E5v2].
+func LegacyTags(torrentTags []string) []string {
+	if slices.ContainsFunc(torrentTags, func(tag string) bool { return strings.HasPrefix(tag, episodePrefix) }) {
+		return nil
+	}
+
+	if len(torrentTags) > 1 && !slices.ContainsFunc(torrentTags, func(tag string) bool { return strings.HasPrefix(tag, titlePrefix) }) {
+		return nil
+	}
+
+	var out []string
+
+	for _, tag := range torrentTags {
+		if legacyEpisodeTagRegexp.MatchString(tag) {
+			out = append(out, tag)
+		}
+	}
+
+	return out
+}
+
+// parseEpisodeTag parses the season episode tag and it's version.
+func parseEpisodeTag(expr *regexp.Regexp, tag string) (Tag, int, bool) {
+	matches := expr.FindStringSubmatch(tag)
+	if len(matches) == 0 {
+		return Tag{}, 0, false
+	}
+
+	var t Tag
+
+	for _, season := range matches[1:3] {
+		if season != "" {
+			t.Seasons = append(t.Seasons, atoi(season))
+		}
+	}
+
+	for _, episode := range matches[3:5] {
+		if episode != "" {
+			value, _ := strconv.ParseFloat(episode, 64)
+			t.Episodes = append(t.Episodes, value)
+		}
+	}
+
+	return t, atoi(matches[5]), true
+}
+
+// formatEpisode pads whole episodes with a zero, keeping half episodes. Example: 05 and 7.5.
+func formatEpisode(episode float64) string {
+	if episode == float64(int(episode)) {
+		return fmt.Sprintf("%02d", int(episode))
+	}
+	return strconv.FormatFloat(episode, 'f', -1, 64)
+}
+
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
@@ -0,0 +1,164 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		want   []string
+	}{
+		{
+			name:   "episode",
+			record: Record{Show: "anilist:154587", Episode: SeasonEpisode(1, 5)},
+			want:   []string{"am:show:anilist:154587", "am:ep:S01E05"},
+		},
+		{
+			name:   "version",
+			record: Record{Episode: SeasonEpisode(1, 5), Version: 2},
+			want:   []string{"am:ep:S01E05", "am:v:2"},
+		},
+		{
+			name:   "first version is omitted",
+			record: Record{Episode: SeasonEpisode(1, 5), Version: 1},
+			want:   []string{"am:ep:S01E05"},
+		},
+		{
+			name:   "half episode",
+			record: Record{Episode: SeasonEpisode(1, 7.5)},
+			want:   []string{"am:ep:S01E7.5"},
+		},
+		{
+			name:   "batch",
+			record: Record{Episode: Tag{Seasons: []int{2}, Episodes: []float64{1, 12}}},
+			want:   []string{"am:ep:S02E01-12"},
+		},
+		{
+			name:   "replacement batch",
+			record: Record{Episode: Tag{Seasons: []int{2}, Episodes: []float64{1, 12}}, Replacement: true},
+			want:   []string{"am:ep:S02E01-12", "am:replacement"},
+		},
+		{
+			name:   "season batch",
+			record: Record{Episode: Tag{Seasons: []int{1, 2}}},
+			want:   []string{"am:ep:S01-02"},
+		},
+		{
+			name:   "movie",
+			record: Record{Show: "mal:1"},
+			want:   []string{"am:show:mal:1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Encode(tt.record)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.record.Episode, Decode(got).Episode)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want Record
+	}{
+		{
+			name: "namespaced",
+			tags: []string{"!show", "am:ep:S01E05", "am:show:anilist:1", "am:v:2"},
+			want: Record{Show: "anilist:1", Episode: SeasonEpisode(1, 5), Version: 2},
+		},
+		{
+			name: "replacement batch",
+			tags: []string{"!show", "am:ep:S02E01-12", "am:replacement"},
+			want: Record{Episode: Tag{Seasons: []int{2}, Episodes: []float64{1, 12}}, Replacement: true},
+		},
+		{
+			name: "user tags sorted after",
+			tags: []string{"!show", "am:ep:S01E05", "watched"},
+			want: Record{Episode: SeasonEpisode(1, 5)},
+		},
+		{
+			name: "namespaced is preferred over legacy",
+			tags: []string{"!show", "S1E4", "am:ep:S01E05"},
+			want: Record{Episode: SeasonEpisode(1, 5)},
+		},
+		{
+			name: "legacy",
+			tags: []string{"!show", "S1E5v2"},
+			want: Record{Episode: SeasonEpisode(1, 5), Version: 2},
+		},
+		{
+			name: "legacy alone",
+			tags: []string{"S1E7.5"},
+			want: Record{Episode: SeasonEpisode(1, 7.5)},
+		},
+		{
+			name: "legacy batch",
+			tags: []string{"!show", "S1E1-13"},
+			want: Record{Episode: Tag{Seasons: []int{1}, Episodes: []float64{1, 13}}},
+		},
+		{
+			name: "legacy with user tags sorted after",
+			tags: []string{"!show", "S2E3", "Seen"},
+			want: Record{Episode: SeasonEpisode(2, 3)},
+		},
+		{
+			name: "movie",
+			tags: []string{"!movie"},
+		},
+		{
+			name: "empty",
+			tags: []string{""},
+		},
+		{
+			name: "nil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Decode(tt.tags))
+		})
+	}
+}
+
+func TestLegacyTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{
+			name: "legacy",
+			tags: []string{"!show", "S1E5v2", "Seen", "Season"},
+			want: []string{"S1E5v2"},
+		},
+		{
+			name: "alone",
+			tags: []string{"S1E5"},
+			want: []string{"S1E5"},
+		},
+		{
+			name: "user season tags",
+			tags: []string{"!show", "S2", "s1e5", "Favorites S1E5"},
+		},
+		{
+			name: "already migrated",
+			tags: []string{"!show", "S1E5", "am:ep:S01E05"},
+		},
+		{
+			name: "without title tag",
+			tags: []string{"S1E5", "Seen"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, LegacyTags(tt.tags))
+		})
+	}
+}
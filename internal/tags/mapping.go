@@ -0,0 +1,90 @@
+package tags
+
+// EpisodeMapping converts between absolute and season episode numbering.
+// Example: with seasons [12, 13], the absolute episode 15 is S2E3.
+type EpisodeMapping struct {
+	// Seasons holds the episode count of each season, starting from the first season.
+	// Zero means the season episode count is unknown.
+	Seasons []int
+}
+
+// count returns the episode count of the given season, or zero if unknown.
+func (m EpisodeMapping) count(season int) int {
+	if season < 1 || season > len(m.Seasons) {
+		return 0
+	}
+
+	return m.Seasons[season-1]
+}
+
+// seasonOf returns the season containing the given absolute episode, and the amount of episodes before it.
+// The last season is unbounded when it's episode count is unknown.
+func (m EpisodeMapping) seasonOf(absolute float64) (season, offset int, ok bool) {
+	for i, count := range m.Seasons {
+		last := i == len(m.Seasons)-1
+		if absolute > float64(offset) && (absolute <= float64(offset+count) || last && count <= 0) {
+			return i + 1, offset, true
+		}
+
+		if count <= 0 {
+			return 0, 0, false
+		}
+
+		offset += count
+	}
+
+	return 0, 0, false
+}
+
+// IsAbsolute returns true when the tag episodes are bigger than the tag's season episode count,
+// so they can only be absolute episode numbers. Example: with seasons [12, 13], S1E15.
+func (m EpisodeMapping) IsAbsolute(t Tag) bool {
+	if len(t.Seasons) != 1 || len(t.Episodes) == 0 {
+		return false
+	}
+
+	count := m.count(t.Seasons[0])
+	return count > 0 && t.LastEpisode() > float64(count)
+}
+
+// ToSeasonal converts tags with absolute episode numbers into season episode numbers.
+// Episodes are considered absolute when they are bigger than the tag's season episode count, see IsAbsolute.
+// Example: with seasons [12, 13], S1E15 becomes S2E3.
+// Tags which can't be converted are returned unchanged.
+func (m EpisodeMapping) ToSeasonal(t Tag) Tag {
+	if !m.IsAbsolute(t) {
+		return t
+	}
+
+	return m.FromAbsolute(t)
+}
+
+// FromAbsolute converts absolute episode numbers into season episode numbers, ignoring the tag's season.
+// It's used when the numbering is known to be absolute, since episodes like 13 are valid in both numberings.
+// Example: with seasons [12, 13], S2E13 becomes S2E1.
+// Tags which can't be converted are returned unchanged.
+func (m EpisodeMapping) FromAbsolute(t Tag) Tag {
+	if len(t.Seasons) != 1 || len(t.Episodes) == 0 {
+		return t
+	}
+
+	season, offset, ok := m.seasonOf(t.FirstEpisode())
+	if !ok {
+		return t
+	}
+
+	// Ranges across multiple seasons can't be represented in a single season.
+	if lastSeason, _, ok := m.seasonOf(t.LastEpisode()); !ok || lastSeason != season {
+		return t
+	}
+
+	episodes := make([]float64, 0, len(t.Episodes))
+	for _, episode := range t.Episodes {
+		episodes = append(episodes, episode-float64(offset))
+	}
+
+	return Tag{
+		Seasons:  []int{season},
+		Episodes: episodes,
+	}
+}
@@ -0,0 +1,114 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpisodeMapping_ToSeasonal(t *testing.T) {
+	mapping := EpisodeMapping{Seasons: []int{12, 13, 0}}
+
+	tests := []struct {
+		name    string
+		mapping EpisodeMapping
+		input   Tag
+		want    Tag
+	}{
+		{
+			name:    "season episode",
+			mapping: mapping,
+			input:   SeasonEpisode(2, 5),
+			want:    SeasonEpisode(2, 5),
+		},
+		{
+			name:    "absolute episode",
+			mapping: mapping,
+			input:   SeasonEpisode(1, 15),
+			want:    SeasonEpisode(2, 3),
+		},
+		{
+			name:    "absolute episode in unknown last season",
+			mapping: mapping,
+			input:   SeasonEpisode(2, 30),
+			want:    SeasonEpisode(3, 5),
+		},
+		{
+			name:    "absolute range",
+			mapping: mapping,
+			input:   Tag{Seasons: []int{1}, Episodes: []float64{13, 25}},
+			want:    Tag{Seasons: []int{2}, Episodes: []float64{1, 13}},
+		},
+		{
+			name:    "range across seasons",
+			mapping: mapping,
+			input:   Tag{Seasons: []int{1}, Episodes: []float64{1, 25}},
+			want:    Tag{Seasons: []int{1}, Episodes: []float64{1, 25}},
+		},
+		{
+			name:    "season batch",
+			mapping: mapping,
+			input:   Tag{Seasons: []int{2}},
+			want:    Tag{Seasons: []int{2}},
+		},
+		{
+			name:    "unknown season count",
+			mapping: mapping,
+			input:   SeasonEpisode(3, 50),
+			want:    SeasonEpisode(3, 50),
+		},
+		{
+			name:  "empty mapping",
+			input: SeasonEpisode(1, 1045),
+			want:  SeasonEpisode(1, 1045),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.mapping.ToSeasonal(tt.input))
+		})
+	}
+}
+
+func TestEpisodeMapping_FromAbsolute(t *testing.T) {
+	mapping := EpisodeMapping{Seasons: []int{12, 13, 0}}
+
+	tests := []struct {
+		name  string
+		input Tag
+		want  Tag
+	}{
+		{
+			name:  "first episode of the second season",
+			input: SeasonEpisode(2, 13),
+			want:  SeasonEpisode(2, 1),
+		},
+		{
+			name:  "last episode of the second season",
+			input: SeasonEpisode(2, 25),
+			want:  SeasonEpisode(2, 13),
+		},
+		{
+			name:  "episode of the first season",
+			input: SeasonEpisode(2, 5),
+			want:  SeasonEpisode(1, 5),
+		},
+		{
+			name:  "range across seasons",
+			input: Tag{Seasons: []int{2}, Episodes: []float64{10, 14}},
+			want:  Tag{Seasons: []int{2}, Episodes: []float64{10, 14}},
+		},
+		{
+			name:  "season batch",
+			input: Tag{Seasons: []int{2}},
+			want:  Tag{Seasons: []int{2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, mapping.FromAbsolute(tt.input))
+		})
+	}
+}
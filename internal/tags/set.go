@@ -0,0 +1,225 @@
+package tags
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// EpisodeRange is an inclusive range of episodes. Half episodes, like 7.5, are single episode ranges.
+type EpisodeRange struct {
+	First float64
+	Last  float64
+}
+
+// isHalf returns true for half episodes, like 7.5.
+// They are only covered by themselves, since E7-8 doesn't contain E7.5.
+func (r EpisodeRange) isHalf() bool {
+	return r.First == r.Last && r.First != float64(int(r.First))
+}
+
+// seasonEpisodes are the episodes of a season. Whole seasons come from season batches, like S01.
+type seasonEpisodes struct {
+	whole  bool
+	ranges []EpisodeRange
+}
+
+// EpisodeSet is a set of episodes across seasons, like S1E1-10, S1E12 and S2.
+// It's immutable, operations return new sets.
+type EpisodeSet struct {
+	seasons map[int]seasonEpisodes
+}
+
+// NewEpisodeSet creates a set with the episodes of the given tags.
+// Tags without episodes are whole seasons, and zero tags are ignored.
+func NewEpisodeSet(tags ...Tag) EpisodeSet {
+	s := EpisodeSet{seasons: make(map[int]seasonEpisodes)}
+
+	for _, t := range tags {
+		if t.IsZero() {
+			continue
+		}
+
+		for season := t.FirstSeason(); season <= t.LastSeason(); season++ {
+			episodes := s.seasons[season]
+
+			switch {
+			case len(t.Episodes) == 0:
+				episodes.whole = true
+			default:
+				episodes.ranges = append(episodes.ranges, EpisodeRange{First: t.FirstEpisode(), Last: t.LastEpisode()})
+			}
+
+			s.seasons[season] = episodes
+		}
+	}
+
+	for season, episodes := range s.seasons {
+		s.seasons[season] = episodes.normalize()
+	}
+
+	return s
+}
+
+// IsEmpty returns true when the set has no episodes.
+func (s EpisodeSet) IsEmpty() bool {
+	return len(s.seasons) == 0
+}
+
+// Seasons returns the seasons with episodes, in order.
+func (s EpisodeSet) Seasons() []int {
+	return slices.Sorted(maps.Keys(s.seasons))
+}
+
+// IsWhole returns true when the whole season is in the set.
+func (s EpisodeSet) IsWhole(season int) bool {
+	return s.seasons[season].whole
+}
+
+// Ranges returns the episode ranges of a season, in order, followed by it's half episodes.
+func (s EpisodeSet) Ranges(season int) []EpisodeRange {
+	return slices.Clone(s.seasons[season].ranges)
+}
+
+// Contains returns true when all episodes of the tag are in the set.
+func (s EpisodeSet) Contains(t Tag) bool {
+	return NewEpisodeSet(t).Difference(s).IsEmpty()
+}
+
+// Union returns the episodes in either set.
+func (s EpisodeSet) Union(other EpisodeSet) EpisodeSet {
+	out := EpisodeSet{seasons: make(map[int]seasonEpisodes, len(s.seasons)+len(other.seasons))}
+
+	for _, set := range []EpisodeSet{s, other} {
+		for season, episodes := range set.seasons {
+			current := out.seasons[season]
+			current.whole = current.whole || episodes.whole
+			current.ranges = append(slices.Clone(current.ranges), episodes.ranges...)
+			out.seasons[season] = current
+		}
+	}
+
+	for season, episodes := range out.seasons {
+		out.seasons[season] = episodes.normalize()
+	}
+
+	return out
+}
+
+// Difference returns the episodes in the set which are not in other.
+// The episode count of whole seasons is unknown, so they are only removed by other whole seasons.
+func (s EpisodeSet) Difference(other EpisodeSet) EpisodeSet {
+	out := EpisodeSet{seasons: make(map[int]seasonEpisodes, len(s.seasons))}
+
+	for season, episodes := range s.seasons {
+		remove, ok := other.seasons[season]
+		switch {
+		case !ok:
+			out.seasons[season] = episodes
+			continue
+		case remove.whole:
+			continue
+		}
+
+		result := seasonEpisodes{whole: episodes.whole}
+		for _, r := range episodes.ranges {
+			result.ranges = append(result.ranges, subtractRanges(r, remove.ranges)...)
+		}
+
+		if result.whole || len(result.ranges) > 0 {
+			out.seasons[season] = result
+		}
+	}
+
+	return out
+}
+
+// String formats the set for logging. Example: S1E1-10,12 S2.
+func (s EpisodeSet) String() string {
+	parts := make([]string, 0, len(s.seasons))
+
+	for _, season := range s.Seasons() {
+		episodes := s.seasons[season]
+		if episodes.whole {
+			parts = append(parts, fmt.Sprintf("S%d", season))
+			continue
+		}
+
+		ranges := make([]string, 0, len(episodes.ranges))
+		for _, r := range episodes.ranges {
+			if r.First == r.Last {
+				ranges = append(ranges, fmt.Sprintf("%v", r.First))
+			} else {
+				ranges = append(ranges, fmt.Sprintf("%v-%v", r.First, r.Last))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("S%dE%s", season, strings.Join(ranges, ",")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// normalize sorts and merges overlapping or consecutive ranges. Example: E1-5 and E6 become E1-6.
+// Half episodes are kept apart, after the merged ranges.
+func (e seasonEpisodes) normalize() seasonEpisodes {
+	ranges := slices.Clone(e.ranges)
+	slices.SortFunc(ranges, func(a, b EpisodeRange) int {
+		if a.First != b.First {
+			return cmp.Compare(a.First, b.First)
+		}
+		return cmp.Compare(a.Last, b.Last)
+	})
+
+	var merged, halves []EpisodeRange
+	for _, r := range ranges {
+		switch {
+		case r.isHalf():
+			if !slices.Contains(halves, r) {
+				halves = append(halves, r)
+			}
+		case len(merged) > 0 && r.First <= merged[len(merged)-1].Last+1:
+			last := &merged[len(merged)-1]
+			last.Last = max(last.Last, r.Last)
+		default:
+			merged = append(merged, r)
+		}
+	}
+
+	return seasonEpisodes{whole: e.whole, ranges: append(merged, halves...)}
+}
+
+// subtractRanges removes the episodes of other from r, splitting it when needed.
+func subtractRanges(r EpisodeRange, other []EpisodeRange) []EpisodeRange {
+	if r.isHalf() {
+		if slices.Contains(other, r) {
+			return nil
+		}
+		return []EpisodeRange{r}
+	}
+
+	pieces := []EpisodeRange{r}
+	for _, o := range other {
+		if o.isHalf() {
+			continue
+		}
+
+		var next []EpisodeRange
+		for _, p := range pieces {
+			if o.Last < p.First || o.First > p.Last {
+				next = append(next, p)
+				continue
+			}
+			if o.First > p.First {
+				next = append(next, EpisodeRange{First: p.First, Last: o.First - 1})
+			}
+			if o.Last < p.Last {
+				next = append(next, EpisodeRange{First: o.Last + 1, Last: p.Last})
+			}
+		}
+		pieces = next
+	}
+
+	return pieces
+}
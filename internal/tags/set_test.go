@@ -0,0 +1,138 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func episodes(season int, first, last float64) Tag {
+	return Tag{Seasons: []int{season}, Episodes: []float64{first, last}}
+}
+
+func TestNewEpisodeSet(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []Tag
+		want string
+	}{
+		{name: "empty", want: ""},
+		{name: "zero tag", tags: []Tag{Zero}, want: ""},
+		{
+			name: "merges consecutive episodes",
+			tags: []Tag{SeasonEpisode(1, 3), episodes(1, 1, 2), SeasonEpisode(1, 4)},
+			want: "S1E1-4",
+		},
+		{
+			name: "keeps gaps",
+			tags: []Tag{episodes(1, 1, 10), SeasonEpisode(1, 12)},
+			want: "S1E1-10,12",
+		},
+		{
+			name: "half episodes",
+			tags: []Tag{SeasonEpisode(1, 7), SeasonEpisode(1, 7.5), SeasonEpisode(1, 8)},
+			want: "S1E7-8,7.5",
+		},
+		{
+			name: "multiple seasons",
+			tags: []Tag{SeasonEpisode(2, 1), {Seasons: []int{1}}, SeasonEpisode(0, 1)},
+			want: "S0E1 S1 S2E1",
+		},
+		{
+			name: "season range",
+			tags: []Tag{{Seasons: []int{1, 2}}},
+			want: "S1 S2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NewEpisodeSet(tt.tags...).String())
+		})
+	}
+}
+
+func TestEpisodeSet_Contains(t *testing.T) {
+	set := NewEpisodeSet(episodes(1, 1, 10), SeasonEpisode(1, 12), Tag{Seasons: []int{2}})
+
+	tests := []struct {
+		name string
+		tag  Tag
+		want bool
+	}{
+		{name: "episode", tag: SeasonEpisode(1, 5), want: true},
+		{name: "gap", tag: SeasonEpisode(1, 11), want: false},
+		{name: "range", tag: episodes(1, 2, 8), want: true},
+		{name: "range over gap", tag: episodes(1, 9, 12), want: false},
+		{name: "half episode", tag: SeasonEpisode(1, 7.5), want: false},
+		{name: "whole season episode", tag: SeasonEpisode(2, 30), want: true},
+		{name: "whole season", tag: Tag{Seasons: []int{2}}, want: true},
+		{name: "partial season batch", tag: Tag{Seasons: []int{1}}, want: false},
+		{name: "other season", tag: SeasonEpisode(3, 1), want: false},
+		{name: "zero", tag: Zero, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, set.Contains(tt.tag))
+		})
+	}
+}
+
+func TestEpisodeSet_Union(t *testing.T) {
+	a := NewEpisodeSet(episodes(1, 1, 5), SeasonEpisode(2, 1))
+	b := NewEpisodeSet(episodes(1, 6, 10), Tag{Seasons: []int{2}})
+
+	require.Equal(t, "S1E1-10 S2", a.Union(b).String())
+	// Sets are immutable.
+	require.Equal(t, "S1E1-5 S2E1", a.String())
+}
+
+func TestEpisodeSet_Difference(t *testing.T) {
+	tests := []struct {
+		name  string
+		set   EpisodeSet
+		other EpisodeSet
+		want  string
+	}{
+		{
+			name:  "splits ranges",
+			set:   NewEpisodeSet(episodes(1, 1, 12)),
+			other: NewEpisodeSet(episodes(1, 1, 10), SeasonEpisode(1, 12)),
+			want:  "S1E11",
+		},
+		{
+			name:  "removes everything",
+			set:   NewEpisodeSet(episodes(1, 1, 12)),
+			other: NewEpisodeSet(episodes(1, 1, 12)),
+			want:  "",
+		},
+		{
+			name:  "whole season removes episodes",
+			set:   NewEpisodeSet(episodes(1, 1, 12)),
+			other: NewEpisodeSet(Tag{Seasons: []int{1}}),
+			want:  "",
+		},
+		{
+			name:  "episodes don't remove whole season",
+			set:   NewEpisodeSet(Tag{Seasons: []int{1}}),
+			other: NewEpisodeSet(episodes(1, 1, 12)),
+			want:  "S1",
+		},
+		{
+			name:  "half episodes",
+			set:   NewEpisodeSet(SeasonEpisode(1, 7.5), SeasonEpisode(1, 8)),
+			other: NewEpisodeSet(episodes(1, 7, 8)),
+			want:  "S1E7.5",
+		},
+		{
+			name:  "other seasons are kept",
+			set:   NewEpisodeSet(SeasonEpisode(1, 1), SeasonEpisode(2, 1)),
+			other: NewEpisodeSet(SeasonEpisode(1, 1)),
+			want:  "S2E1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.set.Difference(tt.other).String())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package animelist
 
 import (
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/sonalys/animeman/internal/utils"
@@ -9,6 +10,7 @@ import (
 
 type ListStatus int
 type AiringStatus int
+type MediaFormat int
 
 type EpisodeSchedule struct {
 	Number  int
@@ -29,16 +31,68 @@ const (
 	AiringStatusUnknown AiringStatus = iota
 	AiringStatusAired
 	AiringStatusAiring
+	AiringStatusNotYetAired
 )
 
+const (
+	MediaFormatUnknown MediaFormat = iota
+	MediaFormatTV
+	MediaFormatMovie
+	MediaFormatOVA
+	MediaFormatONA
+	MediaFormatSpecial
+)
+
+var listStatusNames = []string{
+	"unknown",
+	"watching",
+	"completed",
+	"onHold",
+	"dropped",
+	"planToWatch",
+	"all",
+}
+
+func (s ListStatus) String() string {
+	if s < 0 || int(s) >= len(listStatusNames) {
+		return listStatusNames[ListStatusUnknown]
+	}
+	return listStatusNames[s]
+}
+
+// ParseListStatus converts a list status name, like planToWatch, into a ListStatus.
+func ParseListStatus(name string) (ListStatus, bool) {
+	for i := range listStatusNames {
+		if strings.EqualFold(listStatusNames[i], name) {
+			return ListStatus(i), true
+		}
+	}
+	return ListStatusUnknown, false
+}
+
 type Entry struct {
-	ListStatus      ListStatus
-	Titles          []string
+	// IDs identifies the entry across providers.
+	IDs IDs
+	// Synonyms are alternative titles, used for searching releases.
+	Synonyms   []string
+	ListStatus ListStatus
+	Titles     []string
+	// Format is the media format, like TV or movie.
+	// Movies are downloaded as a single release, and specials are tagged as season zero.
+	Format          MediaFormat
 	AiringStatus    AiringStatus
 	StartDate       time.Time
 	EndDate         time.Time
 	NumEpisodes     int
 	EpisodeSchedule []EpisodeSchedule
+	// Progress is the number of episodes the user has watched.
+	Progress int
+	// Season is the season number detected from the entry relations.
+	// Zero means unknown.
+	Season int
+	// SeasonEpisodes is the episode count of each season, starting from the first season up to the entry season.
+	// Zero means the season episode count is unknown.
+	SeasonEpisodes []int
 }
 
 func NewEntry(
@@ -0,0 +1,116 @@
+package animelist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IDs holds the identifiers of an anime across providers.
+// Zero means the identifier is unknown.
+type IDs struct {
+	AniList int
+	MAL     int
+	AniDB   int
+	Kitsu   int
+	TVDB    int
+}
+
+func (ids IDs) IsZero() bool {
+	return ids == IDs{}
+}
+
+// Key returns a stable identity for the anime, independent from it's titles.
+// Example: anilist:21.
+// It returns an empty string when no identifier is known.
+// The key changes when a more preferred identifier becomes known, so stored keys should be matched with Keys or Shares.
+func (ids IDs) Key() string {
+	if keys := ids.Keys(); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// Keys returns the key of every known identifier, from the most preferred. Example: [anilist:21 mal:21].
+func (ids IDs) Keys() []string {
+	var keys []string
+	for _, id := range []struct {
+		provider string
+		id       int
+	}{
+		{"anilist", ids.AniList},
+		{"mal", ids.MAL},
+		{"anidb", ids.AniDB},
+		{"kitsu", ids.Kitsu},
+		{"tvdb", ids.TVDB},
+	} {
+		if id.id > 0 {
+			keys = append(keys, fmt.Sprintf("%s:%d", id.provider, id.id))
+		}
+	}
+	return keys
+}
+
+// ParseKey parses a key created by Key, returning the identifier it contains.
+func ParseKey(key string) (IDs, bool) {
+	provider, value, ok := strings.Cut(key, ":")
+	if !ok {
+		return IDs{}, false
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return IDs{}, false
+	}
+
+	switch provider {
+	case "anilist":
+		return IDs{AniList: id}, true
+	case "mal":
+		return IDs{MAL: id}, true
+	case "anidb":
+		return IDs{AniDB: id}, true
+	case "kitsu":
+		return IDs{Kitsu: id}, true
+	case "tvdb":
+		return IDs{TVDB: id}, true
+	}
+	return IDs{}, false
+}
+
+// Shares returns true when both have the same identifier for any provider.
+func (ids IDs) Shares(other IDs) bool {
+	same := func(a, b int) bool { return a > 0 && a == b }
+	return same(ids.AniList, other.AniList) ||
+		same(ids.MAL, other.MAL) ||
+		same(ids.AniDB, other.AniDB) ||
+		same(ids.Kitsu, other.Kitsu) ||
+		same(ids.TVDB, other.TVDB)
+}
+
+// HasKey returns true when the key identifies the same anime, even if it's not the current Key.
+// Example: mal:5114 is a key of an anime with both MAL and AniList identifiers.
+func (ids IDs) HasKey(key string) bool {
+	parsed, ok := ParseKey(key)
+	return ok && ids.Shares(parsed)
+}
+
+// Merge fills the unknown identifiers with the ones from other.
+func (ids IDs) Merge(other IDs) IDs {
+	if ids.AniList == 0 {
+		ids.AniList = other.AniList
+	}
+	if ids.MAL == 0 {
+		ids.MAL = other.MAL
+	}
+	if ids.AniDB == 0 {
+		ids.AniDB = other.AniDB
+	}
+	if ids.Kitsu == 0 {
+		ids.Kitsu = other.Kitsu
+	}
+	if ids.TVDB == 0 {
+		ids.TVDB = other.TVDB
+	}
+	return ids
+}
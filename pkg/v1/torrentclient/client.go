@@ -1,10 +1,41 @@
 package torrentclient
 
 import (
-	"fmt"
+	"errors"
+	"time"
 )
 
-var ErrUnauthorized = fmt.Errorf("unauthorized")
+var (
+	// ErrUnauthorized is returned when the torrent client rejects the credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is returned when the torrent, or the endpoint, doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when the request conflicts with the torrent state, like moving to an invalid path.
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable is returned when the torrent client can't be reached, even after retrying.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// State is the torrent state, normalized across torrent clients.
+type State string
+
+const (
+	StateUnknown     State = "unknown"
+	StateDownloading State = "downloading"
+	// StateStalled is a torrent downloading without peers, or still waiting for it's metadata.
+	StateStalled  State = "stalled"
+	StateSeeding  State = "seeding"
+	StatePaused   State = "paused"
+	StateQueued   State = "queued"
+	StateChecking State = "checking"
+	StateError    State = "error"
+)
+
+const (
+	// FilePrioritySkip doesn't download the file.
+	FilePrioritySkip   FilePriority = 0
+	FilePriorityNormal FilePriority = 1
+)
 
 type (
 	Torrent struct {
@@ -12,6 +43,50 @@ type (
 		Category string
 		Hash     string
 		Tags     []string
+		// Progress is the download progress, from 0 to 1.
+		Progress float64
+		// Ratio is the upload ratio.
+		Ratio float64
+		// SeedingTime is how long the torrent has been seeding.
+		SeedingTime time.Duration
+		State       State
+		AddedOn     time.Time
+		// LastActivity is the last time the torrent transferred data. Zero when it never did.
+		LastActivity time.Time
+		// SavePath is the directory the torrent files are saved into, as seen by the torrent client.
+		SavePath string
+		// Limits are the torrent transfer limits.
+		Limits TransferLimits
+		// ShareLimits are the torrent seeding limits. Zero values use the torrent client global limits.
+		ShareLimits ShareLimits
+	}
+
+	// File is a file of a torrent.
+	File struct {
+		// Index identifies the file when setting it's priority.
+		Index int
+		// Name is the file path, relative to the torrent save path. Example: Show/Show - 05.mkv.
+		Name string
+		// Size is in bytes.
+		Size     int64
+		Priority FilePriority
+	}
+
+	// FilePriority is the download priority of a torrent file.
+	FilePriority int
+
+	// TransferLimits are the speed limits of torrents, in bytes per second. Zero is unlimited.
+	TransferLimits struct {
+		Upload   int64
+		Download int64
+	}
+
+	// ShareLimits are the seeding limits of torrents. Zero values use the torrent client global limits.
+	ShareLimits struct {
+		// Ratio is the upload ratio limit, like 2.0.
+		Ratio float64
+		// SeedingTime is the seeding time limit.
+		SeedingTime time.Duration
 	}
 
 	AddTorrentConfig struct {
@@ -21,6 +96,13 @@ type (
 		SavePath string
 		Category string
 		Paused   bool
+		// TopOfQueue adds the torrent to the top of the download queue.
+		TopOfQueue bool
+		// Sequential downloads the pieces in order.
+		Sequential bool
+		// FirstLastPiecePriority downloads the first and last pieces first, allowing previews.
+		FirstLastPiecePriority bool
+		Limits                 TransferLimits
 	}
 
 	ListTorrentConfig struct {